@@ -0,0 +1,117 @@
+package truecoach
+
+import (
+	"context"
+	"time"
+)
+
+// AchievementType identifies an achievement.
+type AchievementType string
+
+const (
+	// AchievementFirstLog is earned on the first day with a logged habit metric.
+	AchievementFirstLog AchievementType = "first_log"
+	// AchievementStreak7 is earned on the 7th consecutive logged day.
+	AchievementStreak7 AchievementType = "streak_7"
+	// AchievementStreak30 is earned on the 30th consecutive logged day.
+	AchievementStreak30 AchievementType = "streak_30"
+	// AchievementWeightLost10kg is earned on the first day a logged weight is
+	// 10 kg below the first weight logged.
+	AchievementWeightLost10kg AchievementType = "weight_lost_10kg"
+)
+
+const lbPerKg = 2.20462
+
+// Achievement is a milestone a client has earned.
+type Achievement struct {
+	// ID is the same as Type; each achievement is earned at most once.
+	ID          string
+	Type        AchievementType
+	Name        string
+	Description string
+	EarnedAt    time.Time
+}
+
+var achievementInfo = map[AchievementType]struct{ name, description string }{
+	AchievementFirstLog:       {"First log", "Logged a habit for the first time."},
+	AchievementStreak7:        {"One week streak", "Logged habits 7 days in a row."},
+	AchievementStreak30:       {"One month streak", "Logged habits 30 days in a row."},
+	AchievementWeightLost10kg: {"10 kg down", "Weighed in 10 kg below the first logged weight."},
+}
+
+// GetAchievements derives the achievements a client has earned from their
+// habit tracker entries of the last two years, in the order earned. The API
+// has no achievements of its own; the ruleset is the AchievementType
+// constants, and EarnedAt is the date of the entry that earned each one.
+//
+// Weight is compared in the units set by WithResponseUnits or, without it,
+// those of the client's profile: the logged-in user's own, or one seen in
+// an earlier GetUserProfile. When neither is known,
+// AchievementWeightLost10kg is never awarded.
+func (c *Client) GetAchievements(authToken, clientID string) ([]Achievement, error) {
+	return c.GetAchievementsContext(context.Background(), authToken, clientID)
+}
+
+// GetAchievementsContext is GetAchievements with a context for cancellation and
+// tracing.
+func (c *Client) GetAchievementsContext(ctx context.Context, authToken, clientID string) ([]Achievement, error) {
+	to := c.now()
+	from := to.Add(-milestoneLookback)
+
+	units, err := c.unitsFor(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	var weightLoss float64 // 10 kg in the response units, or 0 if unknown
+	switch units {
+	case UnitsMetric:
+		weightLoss = 10
+	case UnitsImperial:
+		weightLoss = 10 * lbPerKg
+	}
+
+	var earned []Achievement
+	award := func(typ AchievementType, at time.Time) {
+		for _, a := range earned {
+			if a.Type == typ {
+				return
+			}
+		}
+		info := achievementInfo[typ]
+		earned = append(earned, Achievement{
+			ID:          string(typ),
+			Type:        typ,
+			Name:        info.name,
+			Description: info.description,
+			EarnedAt:    at,
+		})
+	}
+
+	var streaks streakCounter
+	var firstWeight *float64
+	err = c.StreamHabitTrackers(ctx, authToken, clientID, from, to, func(t HabitTrackerTracking) error {
+		if !t.Logged() {
+			return nil
+		}
+		award(AchievementFirstLog, t.Date.Time)
+		streak := streaks.add(t.Date)
+		if streak >= 7 {
+			award(AchievementStreak7, t.Date.Time)
+		}
+		if streak >= 30 {
+			award(AchievementStreak30, t.Date.Time)
+		}
+		if w := t.Weight.Ptr(); w != nil && weightLoss > 0 {
+			if firstWeight == nil {
+				firstWeight = w
+			} else if *firstWeight-*w >= weightLoss {
+				award(AchievementWeightLost10kg, t.Date.Time)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return earned, nil
+}
@@ -0,0 +1,70 @@
+package truecoach
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWeightLossAchievementUnits(t *testing.T) {
+	clock := WithClock(func() time.Time {
+		return time.Date(2026, 4, 20, 12, 0, 0, 0, time.UTC)
+	})
+	habits := habitServer(t, map[string]string{
+		"2026-01-05": `{"id":1,"weight":90}`,
+		"2026-03-02": `{"id":2,"weight":79.5}`, // 10.5 down
+	})
+	server := func(units string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case PathOAuthToken:
+				writeJSON(w, http.StatusOK, `{"access_token":"a","user_id":1}`)
+			case "/users/1":
+				writeJSON(w, http.StatusOK, `{"user":{"id":1,"client_id":5,"units":"`+units+`"}}`)
+			default:
+				habits(w, r)
+			}
+		}
+	}
+	tests := []struct {
+		name    string
+		profile string
+		login   bool
+		opts    []Option
+		want    bool
+	}{
+		{name: "metric profile", profile: "metric", login: true, want: true},
+		{name: "imperial profile", profile: "imperial", login: true, want: false},
+		{name: "option overrides profile", profile: "imperial", login: true, opts: []Option{WithResponseUnits(UnitsMetric)}, want: true},
+		{name: "option without a profile", opts: []Option{WithResponseUnits(UnitsMetric)}, want: true},
+		{name: "units unknown", profile: "metric", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, server(tt.profile), append(tt.opts, clock)...)
+			token := "token"
+			if tt.login {
+				if _, err := c.Login("me@example.com", "secret"); err != nil {
+					t.Fatal(err)
+				}
+				token = ""
+			}
+			got, err := c.GetAchievements(token, "5")
+			if err != nil {
+				t.Fatal(err)
+			}
+			earned := false
+			for _, a := range got {
+				if a.Type == AchievementWeightLost10kg {
+					earned = true
+					if want := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC); !a.EarnedAt.Equal(want) {
+						t.Errorf("earned at %v, want %v", a.EarnedAt, want)
+					}
+				}
+			}
+			if earned != tt.want {
+				t.Errorf("weight loss earned = %v, want %v; achievements %+v", earned, tt.want, got)
+			}
+		})
+	}
+}
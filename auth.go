@@ -0,0 +1,314 @@
+package truecoach
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"resty.dev/v3"
+)
+
+// refreshRetryDelay is how long the background refresher waits after a
+// failed refresh before trying again.
+const refreshRetryDelay = 30 * time.Second
+
+// autoRefreshMargin is how close to expiry a stored token is refreshed
+// before a request uses it.
+const autoRefreshMargin = 60 * time.Second
+
+// ErrNoRefreshToken is returned by Refresh when the Client holds no refresh token.
+var ErrNoRefreshToken = errors.New("no refresh token (log in first)")
+
+// ErrNoAccessToken is returned by Login and Refresh when the token endpoint
+// answers with success but no access_token. The stored token is kept.
+var ErrNoAccessToken = errors.New("token response has no access_token")
+
+// ErrNoToken is returned, before anything is sent, when a method is called
+// with an empty authToken and the Client holds no token either.
+var ErrNoToken = errors.New("no access token (log in or pass authToken)")
+
+// ErrLoginThrottled matches a LoginThrottledError with errors.Is.
+var ErrLoginThrottled = errors.New("login throttled")
+
+// LoginThrottledError is returned by Login and Refresh when the OAuth
+// endpoint rejects the request with 429 Too Many Requests. Logins are never
+// retried automatically; wait RetryAfter before trying again.
+type LoginThrottledError struct {
+	// RetryAfter is the wait the server asked for, or zero if it didn't say.
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("login throttled, retry after %s", e.RetryAfter)
+	}
+	return "login throttled"
+}
+
+func (e *LoginThrottledError) Is(target error) bool { return target == ErrLoginThrottled }
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(res *resty.Response) time.Duration {
+	v := res.Header().Get("Retry-After")
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// tokenState is the token stored on the Client by Login and Refresh.
+type tokenState struct {
+	accessToken  string
+	refreshToken string
+	expiresAt    time.Time
+	lifetime     time.Duration // as issued; zero when unknown
+	userID       string
+}
+
+// requestToken posts a grant to the OAuth token endpoint and stores the result.
+// See DiscoverOAuth for how the endpoint is chosen.
+func (c *Client) requestToken(ctx context.Context, grant map[string]string) (*TokenResponse, error) {
+	endpoint := c.tokenEndpoint()
+	req := c.httpClient.R().SetContext(ctx).SetBody(grant)
+	res, err := c.execute(req, http.MethodPost, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode() == http.StatusTooManyRequests {
+		return nil, &LoginThrottledError{RetryAfter: retryAfter(res)}
+	}
+	if err := c.checkStatus(res); err != nil {
+		return nil, err
+	}
+	if err := c.checkContentType(res); err != nil {
+		return nil, err
+	}
+	var out TokenResponse
+	if err := c.decode(endpoint, res.Bytes(), &out); err != nil {
+		return nil, err
+	}
+	if out.AccessToken == "" {
+		return nil, ErrNoAccessToken
+	}
+	if out.RefreshToken == "" {
+		// Servers that don't rotate refresh tokens leave them out of refresh
+		// responses; the one just used is still valid.
+		out.RefreshToken = grant["refresh_token"]
+	}
+	c.setToken(&out)
+	return &out, nil
+}
+
+// setToken stores tok on the Client and wakes the background refresher.
+func (c *Client) setToken(tok *TokenResponse) {
+	var expiresAt time.Time
+	lifetime := time.Duration(tok.ExpiresIn) * time.Second
+	if lifetime > 0 {
+		expiresAt = time.Now().Add(lifetime)
+	}
+	c.mu.Lock()
+	userID := tok.UserID.String()
+	if userID == "" {
+		// Refresh responses may leave out user_id; the user hasn't changed.
+		userID = c.token.userID
+	}
+	c.token = tokenState{
+		accessToken:  tok.AccessToken,
+		refreshToken: tok.RefreshToken,
+		expiresAt:    expiresAt,
+		lifetime:     max(lifetime, 0),
+		userID:       userID,
+	}
+	c.mu.Unlock()
+	c.wakeRefresher()
+}
+
+// wakeRefresher tells the background refresher, if any, that the stored
+// token changed.
+func (c *Client) wakeRefresher() {
+	if c.tokenChanged != nil {
+		select {
+		case c.tokenChanged <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// SetAccessToken stores a bearer token obtained elsewhere, e.g. from a
+// saved session, on the Client. Later calls may then pass an empty
+// authToken, as after Login. The token has no refresh token or known
+// expiry, so it is not refreshed. It may belong to another user than an
+// earlier Login, so the stored user ID is cleared and ClientID returns
+// ErrNoUserID until the next Login.
+func (c *Client) SetAccessToken(token string) {
+	c.mu.Lock()
+	c.token = tokenState{accessToken: token}
+	c.mu.Unlock()
+	c.wakeRefresher()
+}
+
+func (c *Client) accessToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token.accessToken
+}
+
+// Refresh exchanges the stored refresh token for a new access token and
+// stores it on the Client.
+func (c *Client) Refresh() (*TokenResponse, error) {
+	return c.RefreshContext(context.Background())
+}
+
+// RefreshContext is Refresh with a context for cancellation and tracing.
+func (c *Client) RefreshContext(ctx context.Context) (*TokenResponse, error) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	return c.refresh(ctx)
+}
+
+// refreshIfExpiring refreshes the stored token if it expires within
+// autoRefreshMargin. Concurrent callers wait for one refresh instead of
+// each making their own.
+func (c *Client) refreshIfExpiring(ctx context.Context) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	c.mu.Lock()
+	expiring := c.token.refreshToken != "" && !c.token.expiresAt.IsZero() &&
+		time.Until(c.token.expiresAt) < autoRefreshMargin
+	c.mu.Unlock()
+	if !expiring {
+		return nil
+	}
+	_, err := c.refresh(ctx)
+	return err
+}
+
+// refreshAfterRejection is called when the server answered 401 to the token
+// rejected. It refreshes the stored token unless another request already
+// replaced it, and reports whether a retry with the stored token is worthwhile.
+func (c *Client) refreshAfterRejection(ctx context.Context, rejected string) bool {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	c.mu.Lock()
+	current, refreshToken := c.token.accessToken, c.token.refreshToken
+	c.mu.Unlock()
+	if current != rejected {
+		return current != ""
+	}
+	if refreshToken == "" {
+		return false
+	}
+	if _, err := c.refresh(ctx); err != nil {
+		c.logger.Warn("truecoach: token refresh after 401 failed", "error", err)
+		return false
+	}
+	return true
+}
+
+// refresh is Refresh for callers holding c.refreshMu.
+func (c *Client) refresh(ctx context.Context) (*TokenResponse, error) {
+	c.mu.Lock()
+	refreshToken := c.token.refreshToken
+	c.mu.Unlock()
+	if refreshToken == "" {
+		return nil, ErrNoRefreshToken
+	}
+	return c.requestToken(ctx, map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": refreshToken,
+	})
+}
+
+// WithBackgroundRefresh starts a goroutine that refreshes the stored token
+// lead before it expires, so long-running services always hold a valid
+// token. Tokens that last less than twice lead are refreshed halfway through
+// their lifetime instead. Refresh failures are logged and retried. Call
+// Client.Close to stop the goroutine.
+func WithBackgroundRefresh(lead time.Duration) Option {
+	return func(c *Client) {
+		c.refreshLead = lead
+	}
+}
+
+func (c *Client) startRefreshLoop() {
+	c.tokenChanged = make(chan struct{}, 1)
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.refreshLoop()
+}
+
+func (c *Client) refreshLoop() {
+	defer close(c.done)
+	// Cancelling on stop aborts an in-flight refresh, so Close doesn't wait
+	// on a token endpoint that never answers.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	for {
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if wait, ok := c.untilRefresh(); ok {
+			timer = time.NewTimer(wait)
+			fire = timer.C
+		}
+		select {
+		case <-c.stop:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-c.tokenChanged:
+			if timer != nil {
+				timer.Stop()
+			}
+		case <-fire:
+			if _, err := c.RefreshContext(ctx); err != nil {
+				c.logger.Error("truecoach: token refresh failed", "error", err)
+				select {
+				case <-c.stop:
+					return
+				case <-time.After(refreshRetryDelay):
+				}
+			}
+		}
+	}
+}
+
+// untilRefresh returns how long to wait before refreshing the stored token.
+// It reports false when there is nothing to refresh. The lead is capped at
+// half the token's lifetime, so a lead longer than the server's tokens last
+// doesn't refresh again as soon as each new token arrives.
+func (c *Client) untilRefresh() (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token.refreshToken == "" || c.token.expiresAt.IsZero() {
+		return 0, false
+	}
+	lead := min(c.refreshLead, c.token.lifetime/2)
+	return max(time.Until(c.token.expiresAt)-lead, 0), true
+}
+
+// Close stops the background token refresher, if any, and waits for it to
+// exit. It is safe to call more than once.
+func (c *Client) Close() error {
+	if c.stop == nil {
+		return nil
+	}
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		<-c.done
+	})
+	return nil
+}
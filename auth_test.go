@@ -0,0 +1,237 @@
+package truecoach
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackgroundRefreshLeadLongerThanLifetime(t *testing.T) {
+	var grants atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		grants.Add(1)
+		writeJSON(w, http.StatusOK, `{"access_token":"a","refresh_token":"r","expires_in":2}`)
+	}, WithBackgroundRefresh(time.Hour))
+	if _, err := c.Login("me@example.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	// With the lead capped at half the 2s lifetime, nothing refreshes yet.
+	time.Sleep(200 * time.Millisecond)
+	if n := grants.Load(); n != 1 {
+		t.Errorf("%d token requests, want only the login", n)
+	}
+}
+
+func TestCloseStopsBackgroundRefresh(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"access_token":"a","refresh_token":"r","expires_in":3600}`)
+	}, WithBackgroundRefresh(time.Minute))
+	if _, err := c.Login("me@example.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	closed := make(chan struct{})
+	go func() {
+		c.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close did not return")
+	}
+	select {
+	case <-c.done:
+	default:
+		t.Error("refresh goroutine still running after Close")
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestCloseCancelsHangingRefresh(t *testing.T) {
+	var grants atomic.Int32
+	refreshing := make(chan struct{})
+	release := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if grants.Add(1) == 1 {
+			writeJSON(w, http.StatusOK, `{"access_token":"a","refresh_token":"r","expires_in":1}`)
+			return
+		}
+		close(refreshing)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}, WithBackgroundRefresh(time.Minute))
+	t.Cleanup(func() { close(release) })
+	if _, err := c.Login("me@example.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-refreshing:
+	case <-time.After(2 * time.Second):
+		t.Fatal("background refresh did not start")
+	}
+	closed := make(chan struct{})
+	go func() {
+		c.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close did not return while the refresh hung")
+	}
+}
+
+// tokenServer issues "old" on login and fresh on refresh, which leaves out
+// refresh_token, and serves /users/7 to whichever bearer tokens accept maps
+// to true.
+func tokenServer(t *testing.T, fresh string, accept map[string]bool, grants, reads *atomic.Int32) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case PathOAuthToken:
+			if grants.Add(1) == 1 {
+				writeJSON(w, http.StatusOK, `{"access_token":"old","refresh_token":"r1","expires_in":3600}`)
+				return
+			}
+			writeJSON(w, http.StatusOK, `{"access_token":"`+fresh+`","expires_in":3600}`)
+		case "/users/7":
+			reads.Add(1)
+			if !accept[r.Header.Get("Authorization")] {
+				writeJSON(w, http.StatusUnauthorized, `{"error":"invalid_token"}`)
+				return
+			}
+			writeJSON(w, http.StatusOK, `{"user":{"id":7}}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			writeJSON(w, http.StatusNotFound, `{}`)
+		}
+	}
+}
+
+func TestRefreshAfterRejection(t *testing.T) {
+	var grants, reads atomic.Int32
+	c := newTestClient(t, tokenServer(t, "new", map[string]bool{"Bearer new": true}, &grants, &reads))
+	if _, err := c.Login("me@example.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Profile("7"); err != nil {
+		t.Fatalf("Profile after refresh: %v", err)
+	}
+	if g, n := grants.Load(), reads.Load(); g != 2 || n != 2 {
+		t.Errorf("%d token requests and %d reads, want 2 of each", g, n)
+	}
+	if got := c.accessToken(); got != "new" {
+		t.Errorf("stored access token = %q, want %q", got, "new")
+	}
+	c.mu.Lock()
+	refreshToken := c.token.refreshToken
+	c.mu.Unlock()
+	if refreshToken != "r1" {
+		t.Errorf("stored refresh token = %q, want the one refresh left out", refreshToken)
+	}
+}
+
+func TestRefreshAfterRejectionRetriesOnce(t *testing.T) {
+	var grants, reads atomic.Int32
+	c := newTestClient(t, tokenServer(t, "newer", nil, &grants, &reads))
+	if _, err := c.Login("me@example.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := c.Profile("7")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("err = %v, want a 401 APIError", err)
+	}
+	if g, n := grants.Load(), reads.Load(); g != 2 || n != 2 {
+		t.Errorf("%d token requests and %d reads, want 2 of each", g, n)
+	}
+}
+
+func TestNoToken(t *testing.T) {
+	var sent atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		sent.Add(1)
+		writeJSON(w, http.StatusOK, `{}`)
+	})
+	if _, err := c.GetUserProfile("", "7"); !errors.Is(err, ErrNoToken) {
+		t.Errorf("err = %v, want ErrNoToken", err)
+	}
+	if n := sent.Load(); n != 0 {
+		t.Errorf("%d requests sent without a token", n)
+	}
+}
+
+func TestSetAccessTokenClearsUserID(t *testing.T) {
+	var auth string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == PathOAuthToken {
+			writeJSON(w, http.StatusOK, `{"access_token":"a","user_id":1}`)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		writeJSON(w, http.StatusOK, `{"user":{"id":1,"client_id":10}}`)
+	})
+	if _, err := c.Login("a@example.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := c.ClientID(); err != nil || id != "10" {
+		t.Fatalf("ClientID() = %q, %v after login", id, err)
+	}
+	c.SetAccessToken("b")
+	if id, err := c.ClientID(); !errors.Is(err, ErrNoUserID) {
+		t.Errorf("ClientID() = %q, %v after SetAccessToken, want ErrNoUserID", id, err)
+	}
+	if _, err := c.Profile("2"); err != nil {
+		t.Fatal(err)
+	}
+	if auth != "Bearer b" {
+		t.Errorf("Authorization = %q, want the set token", auth)
+	}
+}
+
+func TestLoginRejectsBadTokenResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		opts        []Option
+		check       func(error) bool
+	}{
+		{"html", "text/html", "<html>gateway</html>", nil, func(err error) bool {
+			var de *DecodeError
+			return errors.As(err, &de)
+		}},
+		{"html validated", "text/html", "<html>gateway</html>", []Option{WithResponseValidation()}, func(err error) bool {
+			return errors.Is(err, ErrUnexpectedContentType)
+		}},
+		{"wrong type", "application/json", `{"access_token":5}`, nil, func(err error) bool {
+			var de *DecodeError
+			return errors.As(err, &de) && de.FieldPath == "access_token"
+		}},
+		{"no access token", "application/json", `{"token_type":"bearer"}`, nil, func(err error) bool {
+			return errors.Is(err, ErrNoAccessToken)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Write([]byte(tt.body))
+			}, tt.opts...)
+			c.SetAccessToken("prev")
+			tok, err := c.Login("me@example.com", "secret")
+			if tok != nil || !tt.check(err) {
+				t.Errorf("Login() = %v, %v", tok, err)
+			}
+			if got := c.accessToken(); got != "prev" {
+				t.Errorf("stored token = %q, want it kept", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package truecoach
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds the response cache. When full, expired entries are
+// dropped first, then the oldest entry.
+const maxCacheEntries = 256
+
+// responseCache is an in-memory cache of successful GET response bodies.
+// A nil *responseCache is valid and caches nothing.
+type responseCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+// cacheKey identifies a request by method, path, query parameters and the
+// bearer token, so responses are never shared between accounts.
+func cacheKey(method, authToken, path string, params map[string]string) string {
+	q := url.Values{}
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	return method + " " + path + "?" + q.Encode() + " " + authToken
+}
+
+func (rc *responseCache) get(key string) ([]byte, bool) {
+	if rc == nil {
+		return nil, false
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	e, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(rc.entries, key)
+		return nil, false
+	}
+	return e.body, true
+}
+
+func (rc *responseCache) set(key string, body []byte) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if _, ok := rc.entries[key]; !ok && len(rc.entries) >= maxCacheEntries {
+		rc.evict()
+	}
+	rc.entries[key] = cacheEntry{body: body, expires: time.Now().Add(rc.ttl)}
+}
+
+// evict makes room for one entry. The caller must hold rc.mu.
+func (rc *responseCache) evict() {
+	now := time.Now()
+	var oldestKey string
+	var oldest time.Time
+	for k, e := range rc.entries {
+		if now.After(e.expires) {
+			delete(rc.entries, k)
+			continue
+		}
+		if oldestKey == "" || e.expires.Before(oldest) {
+			oldestKey, oldest = k, e.expires
+		}
+	}
+	if len(rc.entries) >= maxCacheEntries {
+		delete(rc.entries, oldestKey)
+	}
+}
+
+func (rc *responseCache) clear() {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	clear(rc.entries)
+}
+
+// WithResponseCache caches successful GET responses in memory for ttl.
+// Entries are keyed by method, path, query parameters and token, and the
+// cache holds at most a few hundred responses. Writes made through the
+// Client drop the cache automatically; call Client.InvalidateCache after
+// changes made elsewhere (another client, the app).
+func WithResponseCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = newResponseCache(ttl)
+	}
+}
+
+// InvalidateCache drops every cached response so the next read goes to the API.
+// It is a no-op when the cache is not enabled.
+func (c *Client) InvalidateCache() {
+	c.cache.clear()
+}
+
+// freshReadKey marks a context whose GET requests skip cached responses.
+type freshReadKey struct{}
+
+// freshRead returns ctx with cached responses skipped, for reads that a
+// write is about to be based on. The fresh response still refills the cache.
+func freshRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshReadKey{}, true)
+}
+
+// isFreshRead reports whether ctx was made by freshRead.
+func isFreshRead(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshReadKey{}).(bool)
+	return fresh
+}
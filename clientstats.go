@@ -0,0 +1,81 @@
+package truecoach
+
+import (
+	"context"
+	"time"
+)
+
+// ClientStats summarizes a client's recent habit data for a coach dashboard.
+// Fields are nil when the data isn't available; Missing says why.
+type ClientStats struct {
+	// CurrentWeight is the latest weight logged in the window.
+	CurrentWeight *float64
+	// WeightChange is the latest minus the earliest weight in the window.
+	WeightChange *float64
+	// AvgDailySteps averages steps over the days that logged them.
+	AvgDailySteps *float64
+	// WorkoutCompletionRate is always nil for now: the package doesn't wrap
+	// workout endpoints yet.
+	WorkoutCompletionRate *float64
+	// LastActive is the date of the latest entry with a logged metric.
+	LastActive *time.Time
+	// Missing lists the stats that couldn't be computed.
+	Missing []string
+}
+
+// GetClientStats computes ClientStats from the habit tracker entries of the
+// last window (ending today, in the local time zone).
+func (c *Client) GetClientStats(authToken, clientID string, window time.Duration) (*ClientStats, error) {
+	return c.GetClientStatsContext(context.Background(), authToken, clientID, window)
+}
+
+// GetClientStatsContext is GetClientStats with a context for cancellation and
+// tracing.
+func (c *Client) GetClientStatsContext(ctx context.Context, authToken, clientID string, window time.Duration) (*ClientStats, error) {
+	to := c.now()
+	from := to.Add(-window)
+
+	var stats ClientStats
+	var firstWeight *float64
+	var steps, stepDays int
+	err := c.StreamHabitTrackers(ctx, authToken, clientID, from, to, func(t HabitTrackerTracking) error {
+		if w := t.Weight.Ptr(); w != nil {
+			if firstWeight == nil {
+				firstWeight = w
+			}
+			stats.CurrentWeight = w
+		}
+		if t.Steps != nil {
+			steps += *t.Steps
+			stepDays++
+		}
+		if t.Logged() {
+			d := t.Date.Time
+			stats.LastActive = &d
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if stats.CurrentWeight == nil {
+		stats.Missing = append(stats.Missing, "no weight logged in window")
+	} else if firstWeight != stats.CurrentWeight {
+		change := *stats.CurrentWeight - *firstWeight
+		stats.WeightChange = &change
+	} else {
+		stats.Missing = append(stats.Missing, "only one weight logged in window")
+	}
+	if stepDays > 0 {
+		avg := float64(steps) / float64(stepDays)
+		stats.AvgDailySteps = &avg
+	} else {
+		stats.Missing = append(stats.Missing, "no steps logged in window")
+	}
+	if stats.LastActive == nil {
+		stats.Missing = append(stats.Missing, "no activity logged in window")
+	}
+	stats.Missing = append(stats.Missing, "workout completion not available")
+	return &stats, nil
+}
@@ -0,0 +1,95 @@
+package truecoach
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"resty.dev/v3"
+)
+
+// WithClock sets the clock the Client reads the current time from, e.g. a
+// fixed time in tests. It drives Today, the "today" end of date windows and
+// the Date header set by WithDateHeader. By default it is time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(c *Client) {
+		c.clock = now
+	}
+}
+
+// WithDateHeader sends a Date header (RFC 1123, in GMT) with every request,
+// taken from the Client's clock. Some proxies and gateways reject requests
+// without one. It is off by default.
+func WithDateHeader() Option {
+	return func(c *Client) {
+		c.dateHeader = true
+	}
+}
+
+// now returns the current time by the Client's clock.
+func (c *Client) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock()
+}
+
+// setDateHeader sets the Date header on req if WithDateHeader is on.
+func (c *Client) setDateHeader(req *resty.Request) {
+	if c.dateHeader {
+		req.SetHeader("Date", c.now().UTC().Format(http.TimeFormat))
+	}
+}
+
+// GetServerTime asks the API for its current time, read from the Date header
+// of a HEAD request, and updates ClockSkew. The header has one-second
+// resolution.
+func (c *Client) GetServerTime() (time.Time, error) {
+	return c.GetServerTimeContext(context.Background())
+}
+
+// GetServerTimeContext is GetServerTime with a context for cancellation and
+// tracing.
+func (c *Client) GetServerTimeContext(ctx context.Context) (time.Time, error) {
+	res, err := c.execute(c.httpClient.R().SetContext(ctx), http.MethodHead, "")
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := http.ParseTime(res.Header().Get("Date"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t, nil
+}
+
+// ClockSkew returns how far the server clock is ahead of the local one, as
+// measured from the Date header of the latest response. It is zero until a
+// response has been seen.
+func (c *Client) ClockSkew() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.skew
+}
+
+// Today returns today's date by the server's clock: the Client's clock
+// corrected by ClockSkew, in the local time zone.
+func (c *Client) Today() Date {
+	return NewDate(c.now().Add(c.ClockSkew()))
+}
+
+// recordSkew updates the clock skew from res's Date header. The server time
+// is compared with the middle of the round trip.
+func (c *Client) recordSkew(res *resty.Response, sent time.Time) {
+	if res == nil {
+		return
+	}
+	server, err := http.ParseTime(res.Header().Get("Date"))
+	if err != nil {
+		return
+	}
+	received := time.Now()
+	local := sent.Add(received.Sub(sent) / 2)
+	c.mu.Lock()
+	c.skew = server.Sub(local).Truncate(time.Second)
+	c.mu.Unlock()
+}
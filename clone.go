@@ -0,0 +1,29 @@
+package truecoach
+
+import (
+	"context"
+	"maps"
+)
+
+// Clone returns a Client for separate use, e.g. one per tenant.
+//
+// The clone shares the original's HTTP transport and connection pool, and
+// its response cache, metrics, tracer and logger. It gets its own copy of
+// the request headers and of the stored token, so Login, Refresh or
+// SetAccessToken on one does not affect the other. The background token
+// refresher, if enabled, keeps serving only the original.
+func (c *Client) Clone() *Client {
+	cc := &Client{
+		httpClient:   c.httpClient.Clone(context.Background()),
+		clientConfig: c.clientConfig,
+	}
+	cc.refreshLead = 0
+	c.mu.Lock()
+	cc.token = c.token
+	cc.oauth = c.oauth
+	cc.clientIDs = maps.Clone(c.clientIDs)
+	cc.zones = maps.Clone(c.zones)
+	cc.clientUnits = maps.Clone(c.clientUnits)
+	c.mu.Unlock()
+	return cc
+}
@@ -9,15 +9,20 @@
 //	truecoach profile
 //	truecoach habits
 //	truecoach update-habit -id 123 -steps 10000
+//	truecoach update-habit -clear weight
 package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/seonixx/truecoach"
 )
@@ -125,24 +130,32 @@ func printJSON(v any) {
 // login authenticates, resolves IDs, and saves config.
 func cmdLogin() {
 	fs := flag.NewFlagSet("login", flag.ExitOnError)
-	email := fs.String("email", "", "account email (required)")
-	password := fs.String("password", "", "account password (required)")
+	email := fs.String("email", "", "account email (default $"+truecoach.EnvEmail+")")
+	password := fs.String("password", "", "account password (default $"+truecoach.EnvPassword+")")
 	fs.Parse(os.Args[2:])
 
-	if *email == "" || *password == "" {
-		fatalf("both -email and -password are required")
+	var creds truecoach.CredentialProvider = truecoach.EnvCredentials{}
+	if *email != "" || *password != "" {
+		if *email == "" || *password == "" {
+			fatalf("both -email and -password are required")
+		}
+		creds = truecoach.StaticCredentials{Email: *email, Password: *password}
 	}
 
 	client := truecoach.NewClient()
 
 	fmt.Fprintln(os.Stderr, "Logging in...")
-	token, err := client.Login(*email, *password)
+	token, err := client.LoginWithProvider(creds)
+	var throttled *truecoach.LoginThrottledError
+	if errors.As(err, &throttled) && throttled.RetryAfter > 0 {
+		fatalf("too many login attempts, try again in %s", throttled.RetryAfter.Round(time.Second))
+	}
 	if err != nil {
 		fatalf("login failed: %v", err)
 	}
 
 	fmt.Fprintln(os.Stderr, "Fetching profile...")
-	profile, err := client.GetUserProfile(token.AccessToken, token.UserID.String())
+	profile, err := client.Profile(token.UserID.String())
 	if err != nil {
 		fatalf("failed to fetch profile: %v", err)
 	}
@@ -162,7 +175,8 @@ func cmdLogin() {
 func cmdProfile() {
 	cfg := loadConfig()
 	client := truecoach.NewClient()
-	profile, err := client.GetUserProfile(cfg.Token, cfg.UserID)
+	client.SetAccessToken(cfg.Token)
+	profile, err := client.Profile(cfg.UserID)
 	if err != nil {
 		fatalf("failed to fetch profile: %v", err)
 	}
@@ -177,7 +191,8 @@ func cmdHabits() {
 
 	cfg := loadConfig()
 	client := truecoach.NewClient()
-	habits, err := client.GetHabitTrackers(cfg.Token, cfg.ClientID, parseDate(*dateStr))
+	client.SetAccessToken(cfg.Token)
+	habits, err := client.HabitTrackers(cfg.ClientID, parseDate(*dateStr))
 	if err != nil {
 		fatalf("failed to fetch habit trackers: %v", err)
 	}
@@ -199,15 +214,17 @@ func cmdUpdateHabit() {
 	hunger := fs.Float64("hunger", 0, "hunger level")
 	stress := fs.Float64("stress", 0, "stress level")
 	notes := fs.String("notes", "", "notes")
+	clearFields := fs.String("clear", "", "comma-separated fields to erase (e.g. \"weight,notes\")")
 	fs.Parse(os.Args[2:])
 
 	date := parseDate(*dateStr)
 
 	cfg := loadConfig()
 	client := truecoach.NewClient()
+	client.SetAccessToken(cfg.Token)
 
 	// Fetch the tracking entry for the date to get its ID.
-	habits, err := client.GetHabitTrackers(cfg.Token, cfg.ClientID, date)
+	habits, err := client.HabitTrackers(cfg.ClientID, date)
 	if err != nil {
 		fatalf("failed to fetch habit trackers: %v", err)
 	}
@@ -243,12 +260,37 @@ func cmdUpdateHabit() {
 			input.Stress = stress
 		case "notes":
 			input.Notes = notes
+		case "clear":
+			input.Clear = parseClear(*clearFields)
 		}
 	})
 
-	result, err := client.UpdateHabitTracker(cfg.Token, cfg.ClientID, trackingID, input)
+	result, err := client.UpdateHabitTracker("", cfg.ClientID, trackingID, input)
 	if err != nil {
 		fatalf("failed to update habit tracker: %v", err)
 	}
 	printJSON(result)
 }
+
+// clearableFields are the habit fields -clear accepts.
+var clearableFields = []string{
+	"steps", "weight", "calories", "protein", "carbs", "fat",
+	"sleep", "energy", "hunger", "stress", "notes",
+}
+
+// parseClear splits the -clear list, trimming spaces and rejecting fields
+// that can't be cleared.
+func parseClear(list string) []string {
+	var fields []string
+	for _, f := range strings.Split(list, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !slices.Contains(clearableFields, f) {
+			fatalf("cannot clear %q (want one of %s)", f, strings.Join(clearableFields, ", "))
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
@@ -0,0 +1,63 @@
+package truecoach
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"resty.dev/v3"
+)
+
+// contentSnippetLen caps how much of an unexpected body goes into an error.
+const contentSnippetLen = 200
+
+// ErrUnexpectedContentType is returned when WithResponseValidation is set and
+// a successful response is not JSON, e.g. an HTML page from a gateway.
+var ErrUnexpectedContentType = errors.New("unexpected content type")
+
+// WithResponseValidation checks that successful responses with a body are
+// JSON before decoding them. Other content types fail with an error
+// wrapping ErrUnexpectedContentType that quotes the start of the body,
+// instead of a JSON syntax error. The snippet passes through the
+// configured redactor.
+func WithResponseValidation() Option {
+	return func(c *Client) {
+		c.validate = true
+	}
+}
+
+// checkContentType returns an error if validation is on and res has a
+// non-empty body that isn't JSON.
+func (c *Client) checkContentType(res *resty.Response) error {
+	if !c.validate || len(res.Bytes()) == 0 {
+		return nil
+	}
+	ct := res.Header().Get("Content-Type")
+	if mt, _, err := mime.ParseMediaType(ct); err == nil &&
+		(mt == "application/json" || strings.HasSuffix(mt, "+json")) {
+		return nil
+	}
+	// Redact before cutting, so a match split by the cut can't slip through.
+	body := res.String()
+	if c.redact != nil {
+		body = c.redact(body)
+	}
+	if snippet := truncateUTF8(body, contentSnippetLen); len(snippet) < len(body) {
+		body = snippet + "..."
+	}
+	return fmt.Errorf("%w %q: %s", ErrUnexpectedContentType, ct, body)
+}
+
+// truncateUTF8 returns s cut to at most n bytes, backing off to the start
+// of a rune so a multi-byte character isn't split.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,60 @@
+package truecoach
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCheckContentTypeSnippet(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"email across the cut", strings.Repeat("x", contentSnippetLen-10) + " jane.doe@example.com</p>"},
+		{"multi-byte runes", strings.Repeat("é", contentSnippetLen)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte(tt.body))
+			}, WithResponseValidation(), WithErrorRedactor(RedactEmails))
+			_, err := c.GetUserProfile("token", "7")
+			if !errors.Is(err, ErrUnexpectedContentType) {
+				t.Fatalf("err = %v, want ErrUnexpectedContentType", err)
+			}
+			msg := err.Error()
+			if strings.Contains(msg, "jane.doe") {
+				t.Errorf("email leaked: %s", msg)
+			}
+			if !utf8.ValidString(msg) {
+				t.Errorf("snippet splits a rune: %q", msg)
+			}
+			if !strings.HasSuffix(msg, "...") {
+				t.Errorf("snippet not marked as truncated: %s", msg)
+			}
+		})
+	}
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 3, "hel"},
+		{"héllo", 2, "h"},
+		{"héllo", 3, "hé"},
+		{"日本", 2, ""},
+	}
+	for _, tt := range tests {
+		if got := truncateUTF8(tt.s, tt.n); got != tt.want {
+			t.Errorf("truncateUTF8(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package truecoach
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Environment variables read by EnvCredentials.
+const (
+	EnvEmail    = "TRUECOACH_EMAIL"
+	EnvPassword = "TRUECOACH_PASSWORD"
+)
+
+// ErrMissingCredentials is returned by EnvCredentials when the email or
+// password is not set.
+var ErrMissingCredentials = errors.New("missing credentials")
+
+// CredentialProvider supplies login credentials on demand, so secrets can
+// come from a vault or keychain only when a login actually happens.
+type CredentialProvider interface {
+	GetCredentials() (email, password string, err error)
+}
+
+// StaticCredentials is a CredentialProvider that returns fixed credentials.
+type StaticCredentials struct {
+	Email    string
+	Password string
+}
+
+func (s StaticCredentials) GetCredentials() (string, string, error) {
+	return s.Email, s.Password, nil
+}
+
+// EnvCredentials is a CredentialProvider that reads TRUECOACH_EMAIL and
+// TRUECOACH_PASSWORD from the environment.
+type EnvCredentials struct{}
+
+func (EnvCredentials) GetCredentials() (string, string, error) {
+	email, password := os.Getenv(EnvEmail), os.Getenv(EnvPassword)
+	if email == "" || password == "" {
+		return "", "", fmt.Errorf("%w: set %s and %s", ErrMissingCredentials, EnvEmail, EnvPassword)
+	}
+	return email, password, nil
+}
+
+// LoginWithProvider logs in with credentials fetched from provider. See Login.
+func (c *Client) LoginWithProvider(provider CredentialProvider) (*TokenResponse, error) {
+	return c.LoginWithProviderContext(context.Background(), provider)
+}
+
+// LoginWithProviderContext is LoginWithProvider with a context for
+// cancellation and tracing.
+func (c *Client) LoginWithProviderContext(ctx context.Context, provider CredentialProvider) (*TokenResponse, error) {
+	email, password, err := provider.GetCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return c.LoginContext(ctx, email, password)
+}
@@ -0,0 +1,112 @@
+package truecoach
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// UnmarshalJSON decodes the modeled tracker fields and collects every other
+// key into CustomFields.
+func (t *HabitTrackerTracking) UnmarshalJSON(data []byte) error {
+	type plain HabitTrackerTracking
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	custom, err := unknownFields(data, reflect.TypeFor[plain]())
+	if err != nil {
+		return err
+	}
+	p.CustomFields = custom
+	*t = HabitTrackerTracking(p)
+	return nil
+}
+
+// MarshalJSON encodes the modeled tracker fields followed by CustomFields.
+func (t HabitTrackerTracking) MarshalJSON() ([]byte, error) {
+	type plain HabitTrackerTracking
+	return marshalWithCustomFields(plain(t), t.CustomFields)
+}
+
+// MarshalJSON encodes the set fields followed by CustomFields, and a null
+// for each key in Clear.
+func (in HabitTrackingUpdateInput) MarshalJSON() ([]byte, error) {
+	type plain HabitTrackingUpdateInput
+	data, err := marshalWithCustomFields(plain(in), in.CustomFields)
+	if err != nil || len(in.Clear) == 0 {
+		return data, err
+	}
+	var merged map[string]any
+	if err := unmarshalUseNumber(data, &merged); err != nil {
+		return nil, err
+	}
+	for _, k := range in.Clear {
+		merged[k] = nil
+	}
+	return json.Marshal(merged)
+}
+
+// unknownFields returns the keys of the JSON object data that don't map to a
+// field of struct type t, or nil if there are none.
+func unknownFields(data []byte, t reflect.Type) (map[string]any, error) {
+	var all map[string]any
+	if err := unmarshalUseNumber(data, &all); err != nil {
+		return nil, err
+	}
+	for name := range jsonFieldNames(t) {
+		delete(all, name)
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+	return all, nil
+}
+
+// marshalWithCustomFields encodes v, a struct, and merges custom into the
+// resulting object. Keys naming a field of v are skipped, even when the
+// field is left out as empty.
+func marshalWithCustomFields(v any, custom map[string]any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(custom) == 0 {
+		return data, err
+	}
+	var merged map[string]any
+	if err := unmarshalUseNumber(data, &merged); err != nil {
+		return nil, err
+	}
+	modeled := jsonFieldNames(reflect.TypeOf(v))
+	for k, val := range custom {
+		if !modeled[k] {
+			merged[k] = val
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// jsonFieldNames returns the JSON keys used by the exported fields of struct type t.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := range t.NumField() {
+		if name, ok := jsonFieldName(t.Field(i)); ok {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// jsonFieldName returns the JSON key of struct field f. It reports false for
+// fields encoding/json skips.
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return name, true
+}
@@ -0,0 +1,86 @@
+package truecoach
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestHabitTrackingUpdateInputMarshal(t *testing.T) {
+	date := NewDate(time.Date(2026, 4, 19, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name   string
+		in     HabitTrackingUpdateInput
+		key    string
+		want   any // decoded value of key
+		absent bool
+	}{
+		{
+			name:   "unchanged",
+			in:     HabitTrackingUpdateInput{Date: date},
+			key:    "weight",
+			absent: true,
+		},
+		{
+			name: "set",
+			in:   HabitTrackingUpdateInput{Date: date, Weight: Float64Ptr(80.5)},
+			key:  "weight",
+			want: 80.5,
+		},
+		{
+			name: "clear",
+			in:   HabitTrackingUpdateInput{Date: date, Clear: []string{"weight"}},
+			key:  "weight",
+			want: nil,
+		},
+		{
+			name: "clear wins over set",
+			in:   HabitTrackingUpdateInput{Date: date, Notes: StringPtr("hi"), Clear: []string{"notes"}},
+			key:  "notes",
+			want: nil,
+		},
+		{
+			name: "custom field",
+			in:   HabitTrackingUpdateInput{Date: date, CustomFields: map[string]any{"water": 2.5}},
+			key:  "water",
+			want: 2.5,
+		},
+		{
+			name:   "custom field colliding with an unset field",
+			in:     HabitTrackingUpdateInput{Date: date, CustomFields: map[string]any{"weight": 999}},
+			key:    "weight",
+			absent: true,
+		},
+		{
+			name: "custom field colliding with a set field",
+			in:   HabitTrackingUpdateInput{Date: date, Weight: Float64Ptr(80.5), CustomFields: map[string]any{"weight": 999}},
+			key:  "weight",
+			want: 80.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got["date"] != "Apr 19, 2026" {
+				t.Errorf("date = %v, want Apr 19, 2026", got["date"])
+			}
+			v, ok := got[tt.key]
+			if tt.absent {
+				if ok {
+					t.Errorf("%s = %v, want absent in %s", tt.key, v, data)
+				}
+				return
+			}
+			if !ok || v != tt.want {
+				t.Errorf("%s = %v (present %v), want %v in %s", tt.key, v, ok, tt.want, data)
+			}
+		})
+	}
+}
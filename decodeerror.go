@@ -0,0 +1,99 @@
+package truecoach
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DecodeError is returned when a response body doesn't decode into the
+// expected type. It wraps the underlying error, so errors.Is and errors.As
+// still see it.
+type DecodeError struct {
+	// Endpoint is the request path.
+	Endpoint string
+	// FieldPath is the dotted path of the offending field, e.g.
+	// "trackings.client_id", or "" if the decoder didn't report one.
+	FieldPath string
+	// Snippet is part of the body around the failure, truncated and passed
+	// through the configured redactor.
+	Snippet string
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	msg := "decode " + e.Endpoint
+	if e.FieldPath != "" {
+		msg += " field " + e.FieldPath
+	}
+	return fmt.Sprintf("%s: %v (near %q)", msg, e.Err, e.Snippet)
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// decodeError wraps err, from decoding data into v for endpoint, in a
+// DecodeError. The offending field is found by walking data alongside v's
+// type and decoding piece by piece, since errors from custom unmarshalers
+// and nested decodes don't carry the full path.
+func (c *Client) decodeError(endpoint string, data []byte, v any, err error) error {
+	de := &DecodeError{Endpoint: endpoint, Err: err}
+	snippet := data
+	if path, value, ok := locateDecodeError(data, reflect.TypeOf(v), ""); ok {
+		de.FieldPath = strings.TrimPrefix(path, ".")
+		snippet = value
+	}
+	de.Snippet = string(snippet)
+	if c.redact != nil {
+		de.Snippet = c.redact(de.Snippet)
+	}
+	de.Snippet = truncateUTF8(de.Snippet, contentSnippetLen)
+	return de
+}
+
+// locateDecodeError returns the path and value of the innermost part of
+// data that fails to decode into type t. It reports false if data decodes.
+func locateDecodeError(data json.RawMessage, t reflect.Type, path string) (string, json.RawMessage, bool) {
+	if t.Kind() == reflect.Pointer {
+		if string(data) == "null" {
+			return "", nil, false
+		}
+		return locateDecodeError(data, t.Elem(), path)
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(data, &fields) != nil {
+			break
+		}
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name, ok := jsonFieldName(f)
+			if !ok {
+				continue
+			}
+			if v, ok := fields[name]; ok {
+				if p, bad, ok := locateDecodeError(v, f.Type, path+"."+name); ok {
+					return p, bad, true
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if reflect.PointerTo(t).Implements(unmarshalerType) {
+			break
+		}
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			break
+		}
+		for i, item := range items {
+			if p, bad, ok := locateDecodeError(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i)); ok {
+				return p, bad, true
+			}
+		}
+	}
+	if json.Unmarshal(data, reflect.New(t).Interface()) != nil {
+		return path, data, true
+	}
+	return "", nil, false
+}
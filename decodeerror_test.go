@@ -0,0 +1,40 @@
+package truecoach
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDecodeErrorSnippet(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"email across the cut", strings.Repeat("x", contentSnippetLen-10) + " jane.doe@example.com"},
+		{"multi-byte runes", strings.Repeat("é", contentSnippetLen)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, http.StatusOK, `{"response":{"trackings":[{"id":1,"steps":"`+tt.value+`"}]}}`)
+			}, WithErrorRedactor(RedactEmails))
+			_, err := c.GetHabitTrackers("token", "5", Today())
+			var de *DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("err = %v, want a *DecodeError", err)
+			}
+			if de.FieldPath != "trackings[0].steps" {
+				t.Errorf("FieldPath = %q", de.FieldPath)
+			}
+			if strings.Contains(de.Snippet, "jane.doe") {
+				t.Errorf("email leaked: %s", de.Snippet)
+			}
+			if !utf8.ValidString(de.Snippet) || len(de.Snippet) > contentSnippetLen {
+				t.Errorf("snippet %q is not a rune-aligned cut", de.Snippet)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package truecoach
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Duration is a habit tracker period, as given by the previous_duration,
+// current_duration and next_duration fields of HabitTrackerResponse.
+// Dates are kept in the API's format; use Start and End to parse them.
+type Duration struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+// Start returns the first day of the period.
+func (d *Duration) Start() (time.Time, error) {
+	date, err := ParseDate(d.StartDate)
+	return date.Time, err
+}
+
+// End returns the last day of the period.
+func (d *Duration) End() (time.Time, error) {
+	date, err := ParseDate(d.EndDate)
+	return date.Time, err
+}
+
+// Label returns the period for display, e.g. "Apr 13 - Apr 19, 2026", or
+// "Dec 29, 2025 - Jan 4, 2026" across years. Dates that don't parse are
+// left out; it returns "" if neither does.
+func (d *Duration) Label() string {
+	start, startErr := d.Start()
+	end, endErr := d.End()
+	switch {
+	case startErr != nil && endErr != nil:
+		return ""
+	case endErr != nil:
+		return start.Format(dateAPIFormat)
+	case startErr != nil:
+		return end.Format(dateAPIFormat)
+	case start.Year() == end.Year():
+		return start.Format("Jan 2") + " - " + end.Format(dateAPIFormat)
+	default:
+		return start.Format(dateAPIFormat) + " - " + end.Format(dateAPIFormat)
+	}
+}
+
+// startDate returns the start of d. It reports false when d is nil (no
+// period in that direction) or has no usable date.
+func (d *Duration) startDate() (Date, bool) {
+	if d == nil {
+		return Date{}, false
+	}
+	date, err := ParseDate(d.StartDate)
+	if err != nil {
+		return Date{}, false
+	}
+	return date, true
+}
+
+// UnmarshalJSON decodes the response, leaving a duration nil when the API
+// sends an empty object for it (no period in that direction).
+func (r *HabitTrackerResponse) UnmarshalJSON(data []byte) error {
+	type plain HabitTrackerResponse
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	for _, d := range []**Duration{&r.PreviousDuration, &r.CurrentDuration, &r.NextDuration} {
+		if *d != nil && (*d).StartDate == "" && (*d).EndDate == "" {
+			*d = nil
+		}
+	}
+	return nil
+}
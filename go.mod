@@ -3,6 +3,9 @@ module github.com/seonixx/truecoach
 go 1.24.3
 
 require (
-	golang.org/x/net v0.43.0 // indirect
-	resty.dev/v3 v3.0.0-beta.4 // indirect
+	go.opentelemetry.io/otel v1.35.0
+	go.opentelemetry.io/otel/trace v1.35.0
+	resty.dev/v3 v3.0.0-beta.4
 )
+
+require golang.org/x/net v0.43.0 // indirect
@@ -0,0 +1,435 @@
+package truecoach
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Energy, Hunger and Stress are ratings on a bounded scale. UIs can use
+// ScaleMax to draw them proportionally.
+const (
+	ScaleMin = 1
+	ScaleMax = 10
+)
+
+// ErrScaleOutOfRange is returned when a scale rating is outside ScaleMin..ScaleMax.
+var ErrScaleOutOfRange = errors.New("scale rating out of range")
+
+// Validate checks the payload before it is sent. It returns an error wrapping
+// ErrScaleOutOfRange if a scale rating is out of bounds.
+func (in HabitTrackingUpdateInput) Validate() error {
+	return errors.Join(
+		checkScale("energy", in.Energy),
+		checkScale("hunger", in.Hunger),
+		checkScale("stress", in.Stress),
+	)
+}
+
+// checkScale returns an error wrapping ErrScaleOutOfRange if value is set
+// and outside ScaleMin..ScaleMax.
+func checkScale(name string, value *float64) error {
+	if value != nil && (*value < ScaleMin || *value > ScaleMax) {
+		return fmt.Errorf("%w: %s=%v (want %d-%d)", ErrScaleOutOfRange, name, *value, ScaleMin, ScaleMax)
+	}
+	return nil
+}
+
+// maxStreakLookback caps how far back GetLoggingStreak walks, in days.
+const maxStreakLookback = 365
+
+// Logged reports whether the entry has at least one habit metric filled in.
+func (t HabitTrackerTracking) Logged() bool {
+	return t.Calories.Valid || t.Protein.Valid || t.Carbs.Valid || t.Fat.Valid ||
+		t.Weight.Valid || t.Sleep.Valid || t.Steps != nil ||
+		t.Energy.Valid || t.Hunger.Valid || t.Stress.Valid || (t.Notes != nil && *t.Notes != "")
+}
+
+// Location returns the profile's time zone, for bucketing times into the
+// client's local days.
+func (p UserProfile) Location() (*time.Location, error) {
+	return time.LoadLocation(p.Timezone)
+}
+
+// dayOf returns the calendar day of t in t's location, normalized to UTC so
+// days from different sources compare correctly.
+func dayOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{time.Date(y, m, d, 0, 0, 0, 0, time.UTC)}
+}
+
+// isoDate returns the key used to index days in maps.
+func isoDate(d Date) string { return d.Format(dateISOFormat) }
+
+// addDays returns d moved by n calendar days.
+func addDays(d Date, n int) Date { return NewDate(d.AddDate(0, 0, n)) }
+
+// habitPeriod fetches the habit tracker period containing day and returns its
+// entries by ISO date along with the first day the period covers. When the
+// response carries no period start, only day itself is treated as covered.
+func (c *Client) habitPeriod(ctx context.Context, authToken, clientID string, day Date) (map[string]HabitTrackerTracking, Date, error) {
+	resp, err := c.GetHabitTrackersContext(ctx, authToken, clientID, day)
+	if err != nil {
+		return nil, Date{}, err
+	}
+	entries := make(map[string]HabitTrackerTracking, len(resp.Trackings))
+	for _, t := range resp.Trackings {
+		entries[isoDate(t.Date)] = t
+	}
+	start, ok := resp.CurrentDuration.startDate()
+	if !ok || start.After(day.Time) {
+		start = day
+	}
+	return entries, start, nil
+}
+
+// streakCounter tracks runs of consecutive days fed to it in ascending order.
+type streakCounter struct {
+	last    Date // latest day added
+	current int  // length of the run ending on last
+	longest int
+}
+
+// add records day as logged and returns the length of the run ending on it.
+// A gap of a day or more starts a new run; the same day again is ignored.
+func (s *streakCounter) add(day Date) int {
+	day = dayOf(day.Time)
+	switch {
+	case !s.last.IsZero() && day.Equal(s.last.Time):
+		return s.current
+	case !s.last.IsZero() && day.Equal(addDays(s.last, 1).Time):
+		s.current++
+	default:
+		s.current = 1
+	}
+	s.last = day
+	s.longest = max(s.longest, s.current)
+	return s.current
+}
+
+// GetLoggingStreak counts consecutive days, ending on asOf, with at least one
+// logged habit metric. A day without data ends the streak; asOf itself not
+// being logged yet gives 0. At most maxStreakLookback (365) days are counted.
+//
+// Days are the client's local days. For asOf in UTC or Local, the zone comes
+// from the client's profile (see UserProfile.Location) when the Client has
+// seen it in GetUserProfile, or can fetch it because clientID is the
+// logged-in user's own; otherwise asOf's location is used. An asOf in any
+// other zone is taken in that zone.
+func (c *Client) GetLoggingStreak(authToken, clientID string, asOf time.Time) (int, error) {
+	return c.GetLoggingStreakContext(context.Background(), authToken, clientID, asOf)
+}
+
+// GetLoggingStreakContext is GetLoggingStreak with a context for cancellation
+// and tracing.
+func (c *Client) GetLoggingStreakContext(ctx context.Context, authToken, clientID string, asOf time.Time) (int, error) {
+	loc, err := c.clientLocation(ctx, clientID, asOf)
+	if err != nil {
+		return 0, err
+	}
+	day := dayOf(asOf.In(loc))
+	var entries map[string]HabitTrackerTracking
+	var start Date
+	streak := 0
+	for streak < maxStreakLookback {
+		if entries == nil || day.Before(start.Time) {
+			var err error
+			entries, start, err = c.habitPeriod(ctx, authToken, clientID, day)
+			if err != nil {
+				return 0, err
+			}
+		}
+		if t, ok := entries[isoDate(day)]; !ok || !t.Logged() {
+			break
+		}
+		streak++
+		day = addDays(day, -1)
+	}
+	return streak, nil
+}
+
+// StreamHabitTrackers calls emit for each habit tracker entry dated from
+// through to (inclusive, by calendar day in each time's location), in date
+// order. It fetches one tracker period at a time and never holds more than
+// that in memory. It stops at the first error from emit or the API, or when
+// ctx is done, and returns that error.
+func (c *Client) StreamHabitTrackers(ctx context.Context, authToken, clientID string, from, to time.Time, emit func(HabitTrackerTracking) error) error {
+	day, end := dayOf(from), dayOf(to)
+	var last Date // latest date emitted so far
+	for !day.After(end.Time) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		resp, err := c.getHabitTrackers(ctx, authToken, clientID, day, HabitTrackerOptions{})
+		if err != nil {
+			return err
+		}
+		entries := slices.Clone(resp.Trackings)
+		slices.SortFunc(entries, func(a, b HabitTrackerTracking) int {
+			return a.Date.Compare(b.Date.Time)
+		})
+		for _, t := range entries {
+			d := dayOf(t.Date.Time)
+			if d.Before(day.Time) || d.After(end.Time) || (!last.IsZero() && !d.After(last.Time)) {
+				continue
+			}
+			if err := emit(t); err != nil {
+				return err
+			}
+			last = d
+		}
+		// Jump to the next period when the response says where it starts.
+		// Otherwise continue after the last entry seen, and let last filter
+		// out entries a refetch of the same period returns again.
+		switch next, ok := resp.NextDuration.startDate(); {
+		case ok && next.After(day.Time):
+			day = dayOf(next.Time)
+		case !last.IsZero() && !last.Before(day.Time):
+			day = addDays(last, 1)
+		default:
+			day = addDays(day, 1)
+		}
+	}
+	return nil
+}
+
+// noteSeparator goes between existing notes and appended text.
+const noteSeparator = "\n"
+
+// ErrNoTrackingEntry is returned when there is no habit tracker entry for a date.
+var ErrNoTrackingEntry = errors.New("no tracking entry for date")
+
+// AppendHabitNote adds text to the notes of the entry for date, keeping what
+// is already there, and writes the entry back. It is not idempotent: calling
+// it twice with the same text appends the text twice, so don't retry a call
+// that may have gone through without checking the notes first.
+func (c *Client) AppendHabitNote(authToken, clientID string, date Date, text string) (*HabitTrackerTracking, error) {
+	return c.AppendHabitNoteContext(context.Background(), authToken, clientID, date, text)
+}
+
+// AppendHabitNoteContext is AppendHabitNote with a context for cancellation and
+// tracing.
+func (c *Client) AppendHabitNoteContext(ctx context.Context, authToken, clientID string, date Date, text string) (*HabitTrackerTracking, error) {
+	// Read past the cache so notes edited elsewhere aren't overwritten.
+	resp, err := c.GetHabitTrackersContext(freshRead(ctx), authToken, clientID, date)
+	if err != nil {
+		return nil, err
+	}
+	var entry *HabitTrackerTracking
+	for i := range resp.Trackings {
+		if isoDate(dayOf(resp.Trackings[i].Date.Time)) == isoDate(dayOf(date.Time)) {
+			entry = &resp.Trackings[i]
+			break
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%w %s", ErrNoTrackingEntry, date)
+	}
+	notes := text
+	if entry.Notes != nil && *entry.Notes != "" {
+		notes = *entry.Notes + noteSeparator + text
+	}
+	return c.UpdateHabitTrackerContext(ctx, authToken, clientID, strconv.Itoa(entry.ID), HabitTrackingUpdateInput{
+		Date:  date,
+		Notes: &notes,
+	})
+}
+
+// GetTrackedDates returns the days from through to (inclusive) that have at
+// least one logged habit metric, in ascending order. Each date is midnight
+// in the client's zone, which is chosen from from the way GetLoggingStreak
+// chooses it from asOf.
+func (c *Client) GetTrackedDates(authToken, clientID string, from, to time.Time) ([]time.Time, error) {
+	return c.GetTrackedDatesContext(context.Background(), authToken, clientID, from, to)
+}
+
+// GetTrackedDatesContext is GetTrackedDates with a context for cancellation and
+// tracing.
+func (c *Client) GetTrackedDatesContext(ctx context.Context, authToken, clientID string, from, to time.Time) ([]time.Time, error) {
+	loc, err := c.clientLocation(ctx, clientID, from)
+	if err != nil {
+		return nil, err
+	}
+	from, to = from.In(loc), to.In(loc)
+	var dates []time.Time
+	err = c.StreamHabitTrackers(ctx, authToken, clientID, from, to, func(t HabitTrackerTracking) error {
+		if t.Logged() {
+			y, m, d := t.Date.Date()
+			dates = append(dates, time.Date(y, m, d, 0, 0, 0, 0, from.Location()))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
+// GetDailyBuckets groups the habit tracker entries dated from through to
+// (inclusive) by calendar day, keyed by ISO date ("2006-01-02"). Every day in
+// the range has a key, with an empty slice if nothing was recorded.
+//
+// Days are the client's local days, in the zone chosen from from the way
+// GetLoggingStreak chooses it from asOf. They are stepped by calendar date,
+// not by 24 hours, so 23- and 25-hour days around DST transitions each get
+// exactly one bucket.
+func (c *Client) GetDailyBuckets(authToken, clientID string, from, to time.Time) (map[string][]HabitTrackerTracking, error) {
+	return c.GetDailyBucketsContext(context.Background(), authToken, clientID, from, to)
+}
+
+// GetDailyBucketsContext is GetDailyBuckets with a context for cancellation and
+// tracing.
+func (c *Client) GetDailyBucketsContext(ctx context.Context, authToken, clientID string, from, to time.Time) (map[string][]HabitTrackerTracking, error) {
+	loc, err := c.clientLocation(ctx, clientID, from)
+	if err != nil {
+		return nil, err
+	}
+	from, to = from.In(loc), to.In(loc)
+	buckets := make(map[string][]HabitTrackerTracking)
+	for day, end := dayOf(from), dayOf(to); !day.After(end.Time); day = addDays(day, 1) {
+		buckets[isoDate(day)] = []HabitTrackerTracking{}
+	}
+	err = c.StreamHabitTrackers(ctx, authToken, clientID, from, to, func(t HabitTrackerTracking) error {
+		key := isoDate(dayOf(t.Date.Time))
+		buckets[key] = append(buckets[key], t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// GetCompletionByWeekday returns, for each weekday in from through to
+// (inclusive), the fraction of those days with at least one logged habit
+// metric. Weekdays that don't occur in the range are left out.
+//
+// Weekdays are assigned by the client's local days, as in GetDailyBuckets.
+func (c *Client) GetCompletionByWeekday(authToken, clientID string, from, to time.Time) (map[time.Weekday]float64, error) {
+	return c.GetCompletionByWeekdayContext(context.Background(), authToken, clientID, from, to)
+}
+
+// GetCompletionByWeekdayContext is GetCompletionByWeekday with a context for
+// cancellation and tracing.
+func (c *Client) GetCompletionByWeekdayContext(ctx context.Context, authToken, clientID string, from, to time.Time) (map[time.Weekday]float64, error) {
+	loc, err := c.clientLocation(ctx, clientID, from)
+	if err != nil {
+		return nil, err
+	}
+	from, to = from.In(loc), to.In(loc)
+	tracked, err := c.GetTrackedDatesContext(ctx, authToken, clientID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	logged := make(map[time.Weekday]int)
+	for _, d := range tracked {
+		logged[d.Weekday()]++
+	}
+	total := make(map[time.Weekday]int)
+	for day, end := dayOf(from), dayOf(to); !day.After(end.Time); day = addDays(day, 1) {
+		total[day.Weekday()]++
+	}
+	completion := make(map[time.Weekday]float64, len(total))
+	for wd, n := range total {
+		completion[wd] = float64(logged[wd]) / float64(n)
+	}
+	return completion, nil
+}
+
+// HasMetric reports whether the entry has a value logged for metric, given
+// by its JSON key (e.g. "weight", "steps", or a custom field key).
+func (t HabitTrackerTracking) HasMetric(metric string) bool {
+	switch metric {
+	case "calories":
+		return t.Calories.Valid
+	case "protein":
+		return t.Protein.Valid
+	case "carbs":
+		return t.Carbs.Valid
+	case "fat":
+		return t.Fat.Valid
+	case "weight":
+		return t.Weight.Valid
+	case "sleep":
+		return t.Sleep.Valid
+	case "steps":
+		return t.Steps != nil
+	case "energy":
+		return t.Energy.Valid
+	case "hunger":
+		return t.Hunger.Valid
+	case "stress":
+		return t.Stress.Valid
+	case "notes":
+		return t.Notes != nil && *t.Notes != ""
+	}
+	return t.CustomFields[metric] != nil
+}
+
+// GetMetricPresence reports, for each day from through to (inclusive) keyed
+// by ISO date, whether metric was logged that day (see HasMetric). Date keys
+// are the client's local days, as in GetDailyBuckets.
+func (c *Client) GetMetricPresence(authToken, clientID, metric string, from, to time.Time) (map[string]bool, error) {
+	return c.GetMetricPresenceContext(context.Background(), authToken, clientID, metric, from, to)
+}
+
+// GetMetricPresenceContext is GetMetricPresence with a context for cancellation
+// and tracing.
+func (c *Client) GetMetricPresenceContext(ctx context.Context, authToken, clientID, metric string, from, to time.Time) (map[string]bool, error) {
+	loc, err := c.clientLocation(ctx, clientID, from)
+	if err != nil {
+		return nil, err
+	}
+	from, to = from.In(loc), to.In(loc)
+	presence := make(map[string]bool)
+	for day, end := dayOf(from), dayOf(to); !day.After(end.Time); day = addDays(day, 1) {
+		presence[isoDate(day)] = false
+	}
+	err = c.StreamHabitTrackers(ctx, authToken, clientID, from, to, func(t HabitTrackerTracking) error {
+		if t.HasMetric(metric) {
+			presence[isoDate(dayOf(t.Date.Time))] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return presence, nil
+}
+
+// GetWeek returns the habit tracker entries for the Monday-to-Sunday week
+// containing anyDayInWeek, keyed by ISO date. All seven days have a key;
+// days without an entry map to nil. The API doesn't expose an account week
+// start, so weeks always start on Monday.
+//
+// The week is found from the client's local day of anyDayInWeek, as in
+// GetDailyBuckets.
+func (c *Client) GetWeek(authToken, clientID string, anyDayInWeek time.Time) (map[string]*HabitTrackerTracking, error) {
+	return c.GetWeekContext(context.Background(), authToken, clientID, anyDayInWeek)
+}
+
+// GetWeekContext is GetWeek with a context for cancellation and tracing.
+func (c *Client) GetWeekContext(ctx context.Context, authToken, clientID string, anyDayInWeek time.Time) (map[string]*HabitTrackerTracking, error) {
+	loc, err := c.clientLocation(ctx, clientID, anyDayInWeek)
+	if err != nil {
+		return nil, err
+	}
+	day := dayOf(anyDayInWeek.In(loc))
+	monday := addDays(day, -((int(day.Weekday()) + 6) % 7))
+	sunday := addDays(monday, 6)
+	week := make(map[string]*HabitTrackerTracking, 7)
+	for d := monday; !d.After(sunday.Time); d = addDays(d, 1) {
+		week[isoDate(d)] = nil
+	}
+	err = c.StreamHabitTrackers(ctx, authToken, clientID, monday.Time, sunday.Time, func(t HabitTrackerTracking) error {
+		week[isoDate(dayOf(t.Date.Time))] = &t
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return week, nil
+}
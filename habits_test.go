@@ -0,0 +1,265 @@
+package truecoach
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetDailyBucketsDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	// Clocks in New York spring forward on 2026-03-08, a 23-hour day.
+	c := newTestClient(t, habitServer(t, map[string]string{
+		"2026-03-07": `{"id":1,"steps":1000}`,
+		"2026-03-08": `{"id":2,"steps":2000}`,
+		"2026-03-09": `{"id":3,"steps":3000}`,
+	}))
+	from := time.Date(2026, 3, 7, 0, 0, 0, 0, ny)
+	to := time.Date(2026, 3, 9, 23, 59, 0, 0, ny)
+	buckets, err := c.GetDailyBuckets("token", "5", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"2026-03-07": 1, "2026-03-08": 2, "2026-03-09": 3}
+	if len(buckets) != len(want) {
+		t.Fatalf("got %d buckets %v, want %d", len(buckets), buckets, len(want))
+	}
+	for day, id := range want {
+		b := buckets[day]
+		if len(b) != 1 || b[0].ID != id {
+			t.Errorf("bucket %s = %+v, want one entry with ID %d", day, b, id)
+		}
+	}
+}
+
+func TestStreakCounter(t *testing.T) {
+	day := func(s string) Date {
+		d, err := ParseDate(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	var s streakCounter
+	steps := []struct {
+		day              string
+		current, longest int
+	}{
+		{"2026-01-01", 1, 1},
+		{"2026-01-02", 2, 2},
+		{"2026-01-02", 2, 2}, // same day again
+		{"2026-01-03", 3, 3},
+		{"2026-01-05", 1, 3}, // one-day gap
+		{"2026-01-06", 2, 3},
+		{"2026-02-01", 1, 3}, // long gap
+		{"2026-02-02", 2, 3},
+		{"2026-02-03", 3, 3},
+		{"2026-02-04", 4, 4},
+	}
+	for _, st := range steps {
+		if got := s.add(day(st.day)); got != st.current {
+			t.Errorf("add(%s) = %d, want %d", st.day, got, st.current)
+		}
+		if s.longest != st.longest {
+			t.Errorf("after %s longest = %d, want %d", st.day, s.longest, st.longest)
+		}
+	}
+}
+
+func TestAppendHabitNoteRepeatedText(t *testing.T) {
+	var sent string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var body struct {
+				HabitTracking struct {
+					Notes string `json:"notes"`
+				} `json:"habit_tracking"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode update: %v", err)
+			}
+			sent = body.HabitTracking.Notes
+			writeJSON(w, http.StatusOK, `{"id":1,"date":"2026-04-19"}`)
+			return
+		}
+		habitServer(t, map[string]string{
+			"2026-04-19": `{"id":1,"notes":"ran\nate well"}`,
+		})(w, r)
+	})
+	date, _ := ParseDate("2026-04-19")
+	if _, err := c.AppendHabitNote("token", "5", date, "ate well"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "ran\nate well\nate well"; sent != want {
+		t.Errorf("sent notes %q, want %q", sent, want)
+	}
+}
+
+func TestAppendHabitNoteSkipsCache(t *testing.T) {
+	notes := `"a"`
+	var sent string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var body struct {
+				HabitTracking struct {
+					Notes string `json:"notes"`
+				} `json:"habit_tracking"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode update: %v", err)
+			}
+			sent = body.HabitTracking.Notes
+			writeJSON(w, http.StatusOK, `{"id":1,"date":"2026-04-19"}`)
+			return
+		}
+		habitServer(t, map[string]string{
+			"2026-04-19": `{"id":1,"notes":` + notes + `}`,
+		})(w, r)
+	}, WithResponseCache(time.Hour))
+	date, _ := ParseDate("2026-04-19")
+	if _, err := c.GetHabitTrackers("token", "5", date); err != nil {
+		t.Fatal(err)
+	}
+	notes = `"a\nchanged-in-app"` // edited outside the Client; the cache is stale
+	if _, err := c.AppendHabitNote("token", "5", date, "x"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "a\nchanged-in-app\nx"; sent != want {
+		t.Errorf("sent notes %q, want %q", sent, want)
+	}
+}
+
+func TestHabitDaysUseClientZone(t *testing.T) {
+	auckland, err := time.LoadLocation("Pacific/Auckland")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	habits := habitServer(t, map[string]string{
+		"2026-04-19": `{"id":1,"steps":1000}`,
+		"2026-04-20": `{"id":2,"steps":2000}`,
+	})
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case PathOAuthToken:
+			writeJSON(w, http.StatusOK, `{"access_token":"a","user_id":1}`)
+		case "/users/1":
+			writeJSON(w, http.StatusOK, `{"user":{"id":1,"client_id":5,"timezone":"Pacific/Auckland"}}`)
+		default:
+			habits(w, r)
+		}
+	}
+	// 20:00 UTC on Sunday the 19th is 08:00 on Monday the 20th in Auckland.
+	asOf := time.Date(2026, 4, 19, 20, 0, 0, 0, time.UTC)
+
+	c := newTestClient(t, handler)
+	if _, err := c.Login("me@example.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.GetLoggingStreak("", "5", asOf); err != nil || got != 2 {
+		t.Errorf("streak in the client's zone = %d, %v; want 2", got, err)
+	}
+
+	dates, err := c.GetTrackedDates("", "5", asOf.AddDate(0, 0, -1), asOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{
+		time.Date(2026, 4, 19, 0, 0, 0, 0, auckland),
+		time.Date(2026, 4, 20, 0, 0, 0, 0, auckland),
+	}
+	if len(dates) != len(want) || !dates[0].Equal(want[0]) || !dates[1].Equal(want[1]) {
+		t.Errorf("tracked dates = %v, want %v", dates, want)
+	}
+
+	completion, err := c.GetCompletionByWeekday("", "5", asOf, asOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(completion) != 1 || completion[time.Monday] != 1 {
+		t.Errorf("completion = %v, want only Monday fully logged", completion)
+	}
+
+	presence, err := c.GetMetricPresence("", "5", "steps", asOf, asOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(presence) != 1 || !presence["2026-04-20"] {
+		t.Errorf("presence = %v, want steps on 2026-04-20 only", presence)
+	}
+
+	buckets, err := c.GetDailyBuckets("", "5", asOf, asOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := buckets["2026-04-20"]; len(buckets) != 1 || len(b) != 1 || b[0].ID != 2 {
+		t.Errorf("buckets = %v, want entry 2 on 2026-04-20 only", buckets)
+	}
+
+	week, err := c.GetWeek("", "5", asOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := week["2026-04-20"]; !ok || e == nil || e.ID != 2 {
+		t.Errorf("week = %v, want the week starting Monday 2026-04-20", week)
+	}
+
+	// A time in a specific zone is the caller's choice and is kept.
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	if got, err := c.GetLoggingStreak("", "5", asOf.In(ny)); err != nil || got != 1 {
+		t.Errorf("streak in New York = %d, %v; want 1", got, err)
+	}
+
+	// Without a profile for the client, UTC days are used.
+	anon := newTestClient(t, handler)
+	if got, err := anon.GetLoggingStreak("token", "5", asOf); err != nil || got != 1 {
+		t.Errorf("streak without a known zone = %d, %v; want 1", got, err)
+	}
+}
+
+func TestHabitDaysProfileLookupFails(t *testing.T) {
+	habits := habitServer(t, map[string]string{
+		"2026-04-19": `{"id":1,"steps":1000}`,
+	})
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case PathOAuthToken:
+			writeJSON(w, http.StatusOK, `{"access_token":"a","user_id":1}`)
+		case "/users/1":
+			writeJSON(w, http.StatusInternalServerError, `{}`)
+		default:
+			habits(w, r)
+		}
+	}
+	c := newTestClient(t, handler, WithRetries(0))
+	if _, err := c.Login("me@example.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	// The profile can't be fetched, so the streak is counted in UTC days.
+	asOf := time.Date(2026, 4, 19, 20, 0, 0, 0, time.UTC)
+	if got, err := c.GetLoggingStreak("", "5", asOf); err != nil || got != 1 {
+		t.Errorf("streak after a failed profile lookup = %d, %v; want 1", got, err)
+	}
+}
+
+func TestEmptyNoteNotLogged(t *testing.T) {
+	empty := ""
+	entry := HabitTrackerTracking{Notes: &empty}
+	if entry.Logged() {
+		t.Error("entry with an empty note counts as logged")
+	}
+	if entry.HasMetric("notes") {
+		t.Error("HasMetric(notes) is true for an empty note")
+	}
+	note := "felt good"
+	entry.Notes = &note
+	if !entry.Logged() || !entry.HasMetric("notes") {
+		t.Error("entry with a note doesn't count as logged")
+	}
+}
@@ -0,0 +1,46 @@
+package truecoach
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient starts a server running handler and returns a Client
+// pointed at it. The server is closed when the test ends.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c := NewClient(append([]Option{WithBaseURL(srv.URL)}, opts...)...)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// writeJSON writes body as a JSON response with status.
+func writeJSON(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
+
+// habitServer serves the habit_trackers endpoint from entries, keyed by ISO
+// date, as JSON objects without the date field. Each response holds only
+// the entry for the requested day, wrapped in the response envelope.
+func habitServer(t *testing.T, entries map[string]string) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		d, err := ParseDate(r.URL.Query().Get("date"))
+		if err != nil {
+			t.Errorf("bad date param: %v", err)
+			writeJSON(w, http.StatusBadRequest, `{}`)
+			return
+		}
+		key := isoDate(d)
+		trackings := "[]"
+		if e, ok := entries[key]; ok {
+			trackings = `[{"date":"` + key + `",` + e[1:] + `]`
+		}
+		writeJSON(w, http.StatusOK, `{"response":{"trackings":`+trackings+`}}`)
+	}
+}
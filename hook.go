@@ -0,0 +1,25 @@
+package truecoach
+
+// WithResponseHook passes every response body read by do through hook
+// before it is decoded, so callers can patch shapes that differ across
+// accounts without forking the package. path is the request path. An
+// error from hook is returned from the call.
+//
+// This is a last-resort compatibility shim. The response cache stores
+// bodies as received, so hook also runs on cached reads.
+func WithResponseHook(hook func(path string, body []byte) ([]byte, error)) Option {
+	return func(c *Client) {
+		c.hook = hook
+	}
+}
+
+// decodeResponse runs the response hook, if any, and decodes data into out.
+func (c *Client) decodeResponse(path string, data []byte, out any) error {
+	if c.hook != nil {
+		var err error
+		if data, err = c.hook(path, data); err != nil {
+			return err
+		}
+	}
+	return c.decode(path, data, out)
+}
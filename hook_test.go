@@ -0,0 +1,55 @@
+package truecoach
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResponseHookPatchesReads(t *testing.T) {
+	var paths []string
+	hook := func(path string, body []byte) ([]byte, error) {
+		paths = append(paths, path)
+		// This account sends IDs as quoted strings.
+		return bytes.ReplaceAll(body, []byte(`"id":"7"`), []byte(`"id":7`)), nil
+	}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/users/") {
+			writeJSON(w, http.StatusOK, `{"user":{"id":"7","client_id":5}}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, `{"response":{"trackings":[{"id":"7","date":"2026-04-19"}]}}`)
+	}, WithResponseHook(hook))
+
+	profile, err := c.GetUserProfile("token", "7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.User.ID != 7 {
+		t.Errorf("profile ID = %d, want 7", profile.User.ID)
+	}
+	habits, err := c.GetHabitTrackers("token", "5", Today())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(habits.Trackings) != 1 || habits.Trackings[0].ID != 7 {
+		t.Errorf("trackings = %+v, want one with ID 7", habits.Trackings)
+	}
+	want := []string{"/users/7", "/clients/5/habit_trackers"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("hook paths = %v, want %v", paths, want)
+	}
+}
+
+func TestResponseHookError(t *testing.T) {
+	errPatch := errors.New("patch failed")
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"user":{"id":7}}`)
+	}, WithResponseHook(func(string, []byte) ([]byte, error) { return nil, errPatch }))
+
+	if _, err := c.GetUserProfile("token", "7"); !errors.Is(err, errPatch) {
+		t.Errorf("err = %v, want %v", err, errPatch)
+	}
+}
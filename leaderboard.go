@@ -0,0 +1,73 @@
+package truecoach
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// leaderboardConcurrency caps how many clients GetStreakLeaderboard fetches
+// at once.
+const leaderboardConcurrency = 4
+
+// StreakEntry is one client's place on a streak leaderboard.
+type StreakEntry struct {
+	ClientID string
+	Streak   int
+	// Rank is 1 for the longest streak; tied streaks share a rank.
+	Rank int
+}
+
+// GetStreakLeaderboard computes each client's current logging streak as of
+// asOf (see GetLoggingStreak) and returns them ranked, longest first, ties
+// ordered by client ID. Clients are fetched a few at a time. If some fail,
+// the others are still ranked and returned along with the joined errors.
+func (c *Client) GetStreakLeaderboard(authToken string, clientIDs []string, asOf time.Time) ([]StreakEntry, error) {
+	return c.GetStreakLeaderboardContext(context.Background(), authToken, clientIDs, asOf)
+}
+
+// GetStreakLeaderboardContext is GetStreakLeaderboard with a context for
+// cancellation and tracing.
+func (c *Client) GetStreakLeaderboardContext(ctx context.Context, authToken string, clientIDs []string, asOf time.Time) ([]StreakEntry, error) {
+	entries := make([]StreakEntry, len(clientIDs))
+	errs := make([]error, len(clientIDs))
+	sem := make(chan struct{}, leaderboardConcurrency)
+	var wg sync.WaitGroup
+	for i, id := range clientIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			streak, err := c.GetLoggingStreakContext(ctx, authToken, id, asOf)
+			if err != nil {
+				errs[i] = fmt.Errorf("client %s: %w", id, err)
+				return
+			}
+			entries[i] = StreakEntry{ClientID: id, Streak: streak}
+		}()
+	}
+	wg.Wait()
+
+	ranked := make([]StreakEntry, 0, len(entries))
+	for i, e := range entries {
+		if errs[i] == nil {
+			ranked = append(ranked, e)
+		}
+	}
+	slices.SortFunc(ranked, func(a, b StreakEntry) int {
+		return cmp.Or(cmp.Compare(b.Streak, a.Streak), cmp.Compare(a.ClientID, b.ClientID))
+	})
+	for i := range ranked {
+		if i > 0 && ranked[i].Streak == ranked[i-1].Streak {
+			ranked[i].Rank = ranked[i-1].Rank
+		} else {
+			ranked[i].Rank = i + 1
+		}
+	}
+	return ranked, errors.Join(errs...)
+}
@@ -0,0 +1,67 @@
+package truecoach
+
+import (
+	"context"
+	"time"
+)
+
+// milestoneLookback is how far back GetEngagementMilestones searches for
+// habit data.
+const milestoneLookback = 2 * 365 * 24 * time.Hour
+
+// Milestones records when a client first engaged with the app, for cohort
+// and retention analysis. Fields are nil when the data isn't available;
+// Missing says why.
+type Milestones struct {
+	// SignupDate is always nil for now: the user profile doesn't carry a
+	// creation date.
+	SignupDate *time.Time
+	// FirstWorkoutDate is always nil for now: the package doesn't wrap
+	// workout endpoints yet.
+	FirstWorkoutDate *time.Time
+	// FirstHabitLoggedDate is the date of the earliest entry with a logged
+	// metric.
+	FirstHabitLoggedDate *time.Time
+	// LongestStreak is the longest run of consecutive logged days.
+	LongestStreak int
+	// Missing lists the milestones that couldn't be computed.
+	Missing []string
+}
+
+// GetEngagementMilestones derives Milestones from the client's habit tracker
+// entries of the last two years (ending today, in the local time zone).
+func (c *Client) GetEngagementMilestones(authToken, clientID string) (*Milestones, error) {
+	return c.GetEngagementMilestonesContext(context.Background(), authToken, clientID)
+}
+
+// GetEngagementMilestonesContext is GetEngagementMilestones with a context for
+// cancellation and tracing.
+func (c *Client) GetEngagementMilestonesContext(ctx context.Context, authToken, clientID string) (*Milestones, error) {
+	to := c.now()
+	from := to.Add(-milestoneLookback)
+
+	var m Milestones
+	var streaks streakCounter
+	err := c.StreamHabitTrackers(ctx, authToken, clientID, from, to, func(t HabitTrackerTracking) error {
+		if !t.Logged() {
+			return nil
+		}
+		if m.FirstHabitLoggedDate == nil {
+			d := t.Date.Time
+			m.FirstHabitLoggedDate = &d
+		}
+		streaks.add(t.Date)
+		m.LongestStreak = streaks.longest
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.Missing = append(m.Missing, "signup date not available")
+	m.Missing = append(m.Missing, "first workout date not available")
+	if m.FirstHabitLoggedDate == nil {
+		m.Missing = append(m.Missing, "no habit logged in the last two years")
+	}
+	return &m, nil
+}
@@ -0,0 +1,30 @@
+package truecoach
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetEngagementMilestonesSparse(t *testing.T) {
+	c := newTestClient(t, habitServer(t, map[string]string{
+		"2025-06-01": `{"id":1}`, // entry with nothing logged
+		"2025-06-10": `{"id":2,"steps":1000}`,
+		"2025-06-11": `{"id":3,"weight":80.5}`,
+		"2025-09-01": `{"id":4,"notes":"back"}`,
+		"2025-09-02": `{"id":5,"steps":2000}`,
+		"2025-09-03": `{"id":6,"steps":3000}`,
+		"2026-04-18": `{"id":7,"steps":4000}`,
+	}), WithClock(func() time.Time {
+		return time.Date(2026, 4, 20, 12, 0, 0, 0, time.UTC)
+	}))
+	m, err := c.GetEngagementMilestones("token", "5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.FirstHabitLoggedDate == nil || !dayOf(*m.FirstHabitLoggedDate).Equal(time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("FirstHabitLoggedDate = %v, want 2025-06-10", m.FirstHabitLoggedDate)
+	}
+	if m.LongestStreak != 3 {
+		t.Errorf("LongestStreak = %d, want 3", m.LongestStreak)
+	}
+}
@@ -0,0 +1,102 @@
+package truecoach
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// NullableFloat is a numeric tracker value that may be absent. Some API
+// deployments send numbers as strings ("72.5"), so it decodes a JSON number,
+// a quoted number, or null (also "" as null).
+type NullableFloat struct {
+	Value float64
+	Valid bool // Valid is true if Value is set.
+}
+
+// NewNullableFloat returns a set NullableFloat.
+func NewNullableFloat(f float64) NullableFloat {
+	return NullableFloat{Value: f, Valid: true}
+}
+
+// Ptr returns the value as a *float64, or nil if not set.
+func (n NullableFloat) Ptr() *float64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Value
+}
+
+func (n NullableFloat) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+func (n *NullableFloat) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*n = NullableFloat{}
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case float64:
+		*n = NewNullableFloat(v)
+	case string:
+		if v == "" {
+			*n = NullableFloat{}
+			return nil
+		}
+		// ParseFloat also takes "NaN" and "Inf", which JSON can't carry back.
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("expected number, got %q", v)
+		}
+		*n = NewNullableFloat(f)
+	default:
+		return fmt.Errorf("expected number, string or null, got %T", v)
+	}
+	return nil
+}
+
+// Bool is a boolean that also decodes the forms some endpoints send
+// instead: 0/1 and the strings "true"/"false", "yes"/"no", "1"/"0".
+// Null and "" decode as false.
+type Bool bool
+
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case nil:
+		*b = false
+	case bool:
+		*b = Bool(v)
+	case float64:
+		if v != 0 && v != 1 {
+			return fmt.Errorf("expected boolean, got %v", v)
+		}
+		*b = v == 1
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "yes", "1":
+			*b = true
+		case "false", "no", "0", "":
+			*b = false
+		default:
+			return fmt.Errorf("expected boolean, got %q", v)
+		}
+	default:
+		return fmt.Errorf("expected boolean, number or string, got %T", v)
+	}
+	return nil
+}
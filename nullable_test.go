@@ -0,0 +1,108 @@
+package truecoach
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNullableFloatUnmarshal(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    NullableFloat
+		wantErr bool
+	}{
+		{in: `72.5`, want: NewNullableFloat(72.5)},
+		{in: `0`, want: NewNullableFloat(0)},
+		{in: `"72.5"`, want: NewNullableFloat(72.5)},
+		{in: `"-3"`, want: NewNullableFloat(-3)},
+		{in: `null`, want: NullableFloat{}},
+		{in: `""`, want: NullableFloat{}},
+		{in: `"heavy"`, wantErr: true},
+		{in: `"NaN"`, wantErr: true},
+		{in: `"Inf"`, wantErr: true},
+		{in: `"-Infinity"`, wantErr: true},
+		{in: `"1e400"`, wantErr: true},
+		{in: `true`, wantErr: true},
+		{in: `[1]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := NewNullableFloat(99) // must be overwritten
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNullableFloatMarshal(t *testing.T) {
+	for _, tt := range []struct {
+		in   NullableFloat
+		want string
+	}{
+		{NewNullableFloat(72.5), `72.5`},
+		{NullableFloat{}, `null`},
+	} {
+		got, err := json.Marshal(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("Marshal(%+v) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBoolUnmarshal(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Bool
+		wantErr bool
+	}{
+		{in: `true`, want: true},
+		{in: `false`, want: false},
+		{in: `1`, want: true},
+		{in: `0`, want: false},
+		{in: `"true"`, want: true},
+		{in: `"false"`, want: false},
+		{in: `"yes"`, want: true},
+		{in: `"no"`, want: false},
+		{in: `"YES"`, want: true},
+		{in: `"1"`, want: true},
+		{in: `"0"`, want: false},
+		{in: `""`, want: false},
+		{in: `null`, want: false},
+		{in: `2`, wantErr: true},
+		{in: `0.5`, wantErr: true},
+		{in: `"maybe"`, wantErr: true},
+		{in: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := !tt.want // must be overwritten
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package truecoach
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+)
+
+// decode unmarshals a response body from endpoint, honoring
+// WithStrictNulls. Failures are returned as a *DecodeError.
+func (c *Client) decode(endpoint string, data []byte, v any) error {
+	if c.strictNulls {
+		if path, ok := findNull(data, reflect.TypeOf(v), ""); ok {
+			return &DecodeError{Endpoint: endpoint, FieldPath: path, Snippet: "null", Err: ErrUnexpectedNull}
+		}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return c.decodeError(endpoint, data, v, err)
+	}
+	return nil
+}
+
+// unmarshalUseNumber is json.Unmarshal with numbers in untyped values decoded
+// as json.Number. CustomFields and ClientID decode with it, so their numbers
+// stay exact.
+func unmarshalUseNumber(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// AsFloat64 returns an untyped JSON number, decoded as float64 or
+// json.Number, as a float64.
+func AsFloat64(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// AsInt64 returns an untyped JSON number, decoded as float64 or json.Number,
+// as an int64. It reports false if the number is not an integer.
+func AsInt64(v any) (int64, bool) {
+	switch v := v.(type) {
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// CustomFloat returns the custom field key as a float64.
+func (t HabitTrackerTracking) CustomFloat(key string) (float64, bool) {
+	return AsFloat64(t.CustomFields[key])
+}
+
+// CustomInt returns the custom field key as an int64.
+func (t HabitTrackerTracking) CustomInt(key string) (int64, bool) {
+	return AsInt64(t.CustomFields[key])
+}
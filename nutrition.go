@@ -0,0 +1,112 @@
+package truecoach
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// adherenceTolerance is how far from a target, as a fraction of it, a day's
+// intake may be and still count as on target.
+const adherenceTolerance = 0.1
+
+// Macros holds calorie and macronutrient amounts. Each is nil when there is
+// no data for it.
+type Macros struct {
+	Calories *float64
+	Protein  *float64
+	Carbs    *float64
+	Fat      *float64
+}
+
+// fields returns pointers to m's fields, for looping over them.
+func (m *Macros) fields() []**float64 {
+	return []**float64{&m.Calories, &m.Protein, &m.Carbs, &m.Fat}
+}
+
+// DailyNutrition is one day's logged intake.
+type DailyNutrition struct {
+	Date time.Time
+	Macros
+}
+
+// NutritionReport summarizes calorie and macro intake over a set of days.
+type NutritionReport struct {
+	// Days lists the days with any of calories or macros logged, in date order.
+	Days []DailyNutrition
+	// Average is the mean daily intake of each macro over the days that
+	// logged it. Days without a value are skipped, not counted as zero.
+	Average Macros
+	// DaysLogged is len(Days).
+	DaysLogged int
+}
+
+// WeeklyNutritionReport builds a NutritionReport from habit tracker entries,
+// typically a week of them. Entries with none of calories or macros logged
+// are left out; unlogged macros on the other entries are skipped.
+func WeeklyNutritionReport(trackings []HabitTrackerTracking) NutritionReport {
+	var r NutritionReport
+	var sums [4]float64
+	var counts [4]int
+	for _, t := range trackings {
+		day := DailyNutrition{
+			Date: t.Date.Time,
+			Macros: Macros{
+				Calories: t.Calories.Ptr(),
+				Protein:  t.Protein.Ptr(),
+				Carbs:    t.Carbs.Ptr(),
+				Fat:      t.Fat.Ptr(),
+			},
+		}
+		logged := false
+		for i, f := range day.fields() {
+			if *f != nil {
+				sums[i] += **f
+				counts[i]++
+				logged = true
+			}
+		}
+		if logged {
+			r.Days = append(r.Days, day)
+		}
+	}
+	slices.SortFunc(r.Days, func(a, b DailyNutrition) int { return a.Date.Compare(b.Date) })
+	for i, f := range r.Average.fields() {
+		if counts[i] > 0 {
+			avg := sums[i] / float64(counts[i])
+			*f = &avg
+		}
+	}
+	r.DaysLogged = len(r.Days)
+	return r
+}
+
+// Adherence returns, for each macro with a target set, the fraction of days
+// logging that macro whose intake was within 10% of the target. Macros
+// without a target, or never logged, are nil.
+func (r NutritionReport) Adherence(targets Macros) Macros {
+	var out Macros
+	targetFields := targets.fields()
+	for i, f := range out.fields() {
+		target := *targetFields[i]
+		if target == nil {
+			continue
+		}
+		hit, n := 0, 0
+		for _, d := range r.Days {
+			v := *d.fields()[i]
+			if v == nil {
+				continue
+			}
+			n++
+			if math.Abs(*v-*target) <= adherenceTolerance*math.Abs(*target) {
+				hit++
+			}
+		}
+		if n > 0 {
+			frac := float64(hit) / float64(n)
+			*f = &frac
+		}
+	}
+	return out
+}
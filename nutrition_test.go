@@ -0,0 +1,113 @@
+package truecoach
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// macroString formats m for test messages, with "-" for nil fields.
+func macroString(m Macros) string {
+	s := ""
+	for _, f := range m.fields() {
+		if *f == nil {
+			s += " -"
+		} else {
+			s += " " + strconv.FormatFloat(**f, 'g', -1, 64)
+		}
+	}
+	return "[" + s[1:] + "]"
+}
+
+func macrosEqual(a, b Macros) bool {
+	af, bf := a.fields(), b.fields()
+	for i := range af {
+		x, y := *af[i], *bf[i]
+		if (x == nil) != (y == nil) || x != nil && *x != *y {
+			return false
+		}
+	}
+	return true
+}
+
+func TestWeeklyNutritionReport(t *testing.T) {
+	day := func(d int) Date { return NewDate(time.Date(2026, 4, d, 0, 0, 0, 0, time.UTC)) }
+	num := NewNullableFloat
+	tests := []struct {
+		name      string
+		trackings []HabitTrackerTracking
+		wantDays  []int
+		wantAvg   Macros
+	}{
+		{
+			name:      "nothing logged",
+			trackings: nil,
+			wantAvg:   Macros{},
+		},
+		{
+			name: "entries without macros left out",
+			trackings: []HabitTrackerTracking{
+				{Date: day(2), Steps: IntPtr(5000)},
+				{Date: day(1), Calories: num(2000)},
+			},
+			wantDays: []int{1},
+			wantAvg:  Macros{Calories: Float64Ptr(2000)},
+		},
+		{
+			name: "nil macros skipped, not averaged as zero",
+			trackings: []HabitTrackerTracking{
+				{Date: day(3), Calories: num(2400), Protein: num(150)},
+				{Date: day(1), Calories: num(1800)},
+				{Date: day(2), Protein: num(100), Fat: num(60)},
+			},
+			wantDays: []int{1, 2, 3},
+			wantAvg:  Macros{Calories: Float64Ptr(2100), Protein: Float64Ptr(125), Fat: Float64Ptr(60)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := WeeklyNutritionReport(tt.trackings)
+			if r.DaysLogged != len(tt.wantDays) || len(r.Days) != len(tt.wantDays) {
+				t.Fatalf("got %d days (DaysLogged %d), want %d", len(r.Days), r.DaysLogged, len(tt.wantDays))
+			}
+			for i, d := range tt.wantDays {
+				if want := day(d).Time; !r.Days[i].Date.Equal(want) {
+					t.Errorf("day %d = %v, want %v", i, r.Days[i].Date, want)
+				}
+			}
+			if !macrosEqual(r.Average, tt.wantAvg) {
+				t.Errorf("average = %s, want %s", macroString(r.Average), macroString(tt.wantAvg))
+			}
+		})
+	}
+}
+
+func TestNutritionReportAdherence(t *testing.T) {
+	days := func(calories ...float64) NutritionReport {
+		var r NutritionReport
+		for _, c := range calories {
+			r.Days = append(r.Days, DailyNutrition{Macros: Macros{Calories: Float64Ptr(c)}})
+		}
+		r.Days = append(r.Days, DailyNutrition{Macros: Macros{Protein: Float64Ptr(100)}})
+		return r
+	}
+	target := Macros{Calories: Float64Ptr(2000), Carbs: Float64Ptr(250)}
+	tests := []struct {
+		name   string
+		report NutritionReport
+		want   Macros
+	}{
+		{"exactly 10% off counts", days(2200, 1800), Macros{Calories: Float64Ptr(1)}},
+		{"just past 10% doesn't", days(2201, 1799), Macros{Calories: Float64Ptr(0)}},
+		{"mixed", days(2000, 2500, 1950, 1000), Macros{Calories: Float64Ptr(0.5)}},
+		{"never logged", days(), Macros{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Protein has no target and carbs are never logged, so both stay nil.
+			if got := tt.report.Adherence(target); !macrosEqual(got, tt.want) {
+				t.Errorf("adherence = %s, want %s", macroString(got), macroString(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package truecoach
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// oauthDiscoveryPath is the RFC 8414 metadata document, served from the host root.
+const oauthDiscoveryPath = "/.well-known/oauth-authorization-server"
+
+// OAuthMetadata lists the OAuth endpoints used by the Client.
+type OAuthMetadata struct {
+	Issuer             string `json:"issuer"`
+	TokenEndpoint      string `json:"token_endpoint"`
+	RevocationEndpoint string `json:"revocation_endpoint"`
+	// Discovered is false when the server has no discovery document and the
+	// built-in paths are used.
+	Discovered bool `json:"-"`
+}
+
+// defaultOAuthMetadata is used when discovery isn't available. Refresh grants
+// go to the token endpoint too.
+var defaultOAuthMetadata = OAuthMetadata{
+	TokenEndpoint: PathOAuthToken,
+}
+
+// DiscoverOAuth fetches the server's OAuth metadata. Login and Refresh then
+// use the discovered token endpoint. If the server has no discovery
+// document, or its token endpoint is on another scheme or host than the
+// base URL, it returns the built-in paths with Discovered false. Passwords
+// are never sent to a host the Client wasn't configured for.
+func (c *Client) DiscoverOAuth() (*OAuthMetadata, error) {
+	return c.DiscoverOAuthContext(context.Background())
+}
+
+// DiscoverOAuthContext is DiscoverOAuth with a context for cancellation and
+// tracing.
+func (c *Client) DiscoverOAuthContext(ctx context.Context) (*OAuthMetadata, error) {
+	u, err := url.Parse(c.httpClient.BaseURL())
+	if err != nil {
+		return nil, err
+	}
+	u.Path = oauthDiscoveryPath
+	res, err := c.execute(c.httpClient.R().SetContext(ctx), http.MethodGet, u.String())
+	if err != nil {
+		return nil, err
+	}
+	md := defaultOAuthMetadata
+	if res.IsSuccess() {
+		var found OAuthMetadata
+		err := json.Unmarshal(res.Bytes(), &found)
+		switch {
+		case err != nil || found.TokenEndpoint == "":
+			// No usable document; keep the built-in paths.
+		case !sameOrigin(u, found.TokenEndpoint):
+			c.logger.Warn("truecoach: ignoring discovered token endpoint on another host", "token_endpoint", found.TokenEndpoint)
+		default:
+			md = found
+			md.Discovered = true
+		}
+	}
+	c.mu.Lock()
+	c.oauth = &md
+	c.mu.Unlock()
+	return &md, nil
+}
+
+// tokenEndpoint returns the discovered token endpoint, or the built-in path.
+func (c *Client) tokenEndpoint() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.oauth == nil {
+		return defaultOAuthMetadata.TokenEndpoint
+	}
+	return c.oauth.TokenEndpoint
+}
+
+// sameOrigin reports whether endpoint is relative, or absolute with the
+// scheme and host of base.
+func sameOrigin(base *url.URL, endpoint string) bool {
+	e, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	if e.Scheme == "" && e.Host == "" {
+		return true
+	}
+	return strings.EqualFold(e.Scheme, base.Scheme) && strings.EqualFold(e.Host, base.Host)
+}
@@ -0,0 +1,44 @@
+package truecoach
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDiscoverOAuthTokenEndpoint(t *testing.T) {
+	var base string
+	tests := []struct {
+		name     string
+		endpoint func() string
+		want     func() string
+		found    bool
+	}{
+		{"relative", func() string { return "/auth/token" }, func() string { return "/auth/token" }, true},
+		{"same host", func() string { return base + "/auth/token" }, func() string { return base + "/auth/token" }, true},
+		{"other host", func() string { return "https://evil.example.com/token" }, func() string { return PathOAuthToken }, false},
+		{"other scheme", func() string { return "ftp" + base[len("http"):] + "/token" }, func() string { return PathOAuthToken }, false},
+		{"protocol-relative", func() string { return "//evil.example.com/token" }, func() string { return PathOAuthToken }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, http.StatusOK, `{"token_endpoint":"`+tt.endpoint()+`"}`)
+			}))
+			defer srv.Close()
+			base = srv.URL
+			c := NewClient(WithBaseURL(srv.URL + "/api"))
+			defer c.Close()
+			md, err := c.DiscoverOAuth()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if md.TokenEndpoint != tt.want() || md.Discovered != tt.found {
+				t.Errorf("got %q (discovered %v), want %q (%v)", md.TokenEndpoint, md.Discovered, tt.want(), tt.found)
+			}
+			if got := c.tokenEndpoint(); got != tt.want() {
+				t.Errorf("tokenEndpoint() = %q, want %q", got, tt.want())
+			}
+		})
+	}
+}
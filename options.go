@@ -0,0 +1,116 @@
+package truecoach
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"resty.dev/v3"
+)
+
+// Option configures a Client. Pass options to NewClient.
+type Option func(*Client)
+
+// WithRestyClient makes the Client send requests through rc instead of a
+// freshly constructed resty client, so shared middleware, tracing and
+// transport tuning are kept.
+//
+// NewClient overrides the base URL and the User-Agent, Accept,
+// Content-Type, Role and Accept-Encoding headers on rc (use WithBaseURL and
+// WithUserAgent to choose the first two). Everything else
+// is left as configured. Because rc is modified in place, don't share it
+// with code that talks to other APIs.
+func WithRestyClient(rc *resty.Client) Option {
+	return func(c *Client) {
+		c.httpClient = rc
+	}
+}
+
+// WithHTTPClient makes the Client send requests through hc, e.g. one with a
+// custom transport or proxy. A copy of hc is wrapped in a new resty client,
+// so hc itself is left as it is; see WithRestyClient for which settings
+// NewClient overrides.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		hc := *hc
+		c.httpClient = resty.NewWithClient(&hc)
+	}
+}
+
+// WithBaseURL sends requests to baseURL instead of the TrueCoach API, e.g.
+// a mock server in tests. It includes the API path prefix, as in the
+// default "https://api.truecoach.co/api".
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithTimeout limits how long each request attempt may take, including
+// reading the response. By default there is no limit.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithUserAgent replaces the default User-Agent header.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithLogger sets the logger used for background events such as failed
+// token refreshes. By default nothing is logged.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithResponseUnits asks the API to return profile and habit tracker values
+// in units rather than the account's own setting, by adding a units query
+// parameter to those requests.
+//
+// The UserProfile height helpers convert based on the Units field the server
+// returns, so they stay correct with or without this option. Don't convert a
+// response yourself on top of a server-side conversion.
+func WithResponseUnits(units Units) Option {
+	return func(c *Client) {
+		c.units = units
+	}
+}
+
+// unitsParams adds the units query parameter to params when WithResponseUnits
+// is set. params may be nil.
+func (c *Client) unitsParams(params map[string]string) map[string]string {
+	if c.units == "" {
+		return params
+	}
+	if params == nil {
+		params = make(map[string]string)
+	}
+	params["units"] = string(c.units)
+	return params
+}
+
+// WithAPIVersion opts into a versioned API by sending
+// "Accept: application/vnd.truecoach.<v>+json" (e.g. v = "v2") instead of
+// plain "application/json".
+//
+// The response types in this package model the default, unversioned API.
+// Newer versions may return shapes they don't decode; use Raw or GetRawJSON
+// for those.
+func WithAPIVersion(v string) Option {
+	return func(c *Client) {
+		c.apiVersion = v
+	}
+}
+
+func (c *Client) acceptHeader() string {
+	if c.apiVersion == "" {
+		return accept
+	}
+	return "application/vnd.truecoach." + c.apiVersion + "+json"
+}
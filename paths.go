@@ -0,0 +1,64 @@
+package truecoach
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// Endpoint path templates, relative to the API base URL. Placeholders use
+// resty's {name} syntax, so the templates work directly with
+// Request.SetPathParam on a resty client pointed at the API.
+const (
+	PathOAuthToken    = "/oauth/token"
+	PathUser          = "/users/{userID}"
+	PathHabitTrackers = "/clients/{clientID}/habit_trackers"
+	PathHabitTracker  = "/clients/{clientID}/habit_trackers/{trackingID}"
+)
+
+// ErrInvalidID is returned when an ID used in a request path is empty or
+// contains characters that would change the path.
+var ErrInvalidID = errors.New("invalid ID")
+
+// EndpointPath fills the placeholders of a path template from name/value
+// pairs, e.g. EndpointPath(PathUser, "userID", "123") returns "/users/123".
+// Values are path-escaped but not validated.
+func EndpointPath(template string, nameValues ...string) string {
+	path := template
+	for i := 0; i+1 < len(nameValues); i += 2 {
+		path = strings.ReplaceAll(path, "{"+nameValues[i]+"}", url.PathEscape(nameValues[i+1]))
+	}
+	return path
+}
+
+// endpoint is EndpointPath for the package's own requests. Each value is
+// cleaned with cleanID first, and a malformed value fails the request
+// before anything is sent.
+func endpoint(template string, nameValues ...string) (string, error) {
+	cleaned := make([]string, len(nameValues))
+	for i := 0; i+1 < len(nameValues); i += 2 {
+		id, err := cleanID(nameValues[i+1])
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", nameValues[i], err)
+		}
+		cleaned[i], cleaned[i+1] = nameValues[i], id
+	}
+	return EndpointPath(template, cleaned...), nil
+}
+
+// cleanID trims surrounding whitespace and slashes from id and rejects
+// values that are empty, dot segments, or contain separators or control
+// characters, so an untrusted ID can't point a request at another path.
+func cleanID(id string) (string, error) {
+	s := strings.Trim(strings.TrimSpace(id), "/")
+	if s == "" || s == "." || s == ".." {
+		return "", fmt.Errorf("%w %q", ErrInvalidID, id)
+	}
+	if strings.ContainsAny(s, `/\?#%`) || strings.ContainsFunc(s, unicode.IsControl) ||
+		strings.ContainsFunc(s, unicode.IsSpace) {
+		return "", fmt.Errorf("%w %q", ErrInvalidID, id)
+	}
+	return s, nil
+}
@@ -0,0 +1,58 @@
+package truecoach
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCleanID(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "123", want: "123"},
+		{in: "  123  ", want: "123"},
+		{in: "/123/", want: "123"},
+		{in: " /123/ ", want: "123"},
+		{in: "", wantErr: true},
+		{in: "   ", wantErr: true},
+		{in: "/", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "..", wantErr: true},
+		{in: "/../", wantErr: true},
+		{in: "a/b", wantErr: true},
+		{in: `a\b`, wantErr: true},
+		{in: "%2e", wantErr: true},
+		{in: "%2e%2e", wantErr: true},
+		{in: "1?admin=1", wantErr: true},
+		{in: "1#x", wantErr: true},
+		{in: "1\x00", wantErr: true},
+		{in: "1\n2", wantErr: true},
+		{in: "1 2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := cleanID(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidID) {
+					t.Fatalf("cleanID(%q) = %q, %v; want ErrInvalidID", tt.in, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("cleanID(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	got, err := endpoint(PathHabitTracker, "clientID", " 5 ", "trackingID", "/9/")
+	if err != nil || got != "/clients/5/habit_trackers/9" {
+		t.Errorf("endpoint = %q, %v; want /clients/5/habit_trackers/9", got, err)
+	}
+	if _, err := endpoint(PathUser, "userID", "../admin"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("endpoint with ../admin: err = %v, want ErrInvalidID", err)
+	}
+}
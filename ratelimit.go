@@ -0,0 +1,64 @@
+package truecoach
+
+import (
+	"strconv"
+	"time"
+
+	"resty.dev/v3"
+)
+
+// resetEpochThreshold separates reset headers given as a Unix time from
+// ones given as seconds from now.
+const resetEpochThreshold = 1_000_000_000
+
+// rateLimit is the rate-limit state from the latest response that had one.
+type rateLimit struct {
+	remaining int
+	resetAt   time.Time
+	seen      bool
+}
+
+// RequestsUntilReset returns how many requests the API said remain in the
+// current rate-limit window and when the window resets, as of the latest
+// response that carried rate-limit headers (X-RateLimit-Remaining and
+// X-RateLimit-Reset, or their unprefixed RateLimit-* forms). It reports
+// false if none has been seen yet. resetAt is zero if the server didn't say.
+func (c *Client) RequestsUntilReset() (remaining int, resetAt time.Time, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rate.remaining, c.rate.resetAt, c.rate.seen
+}
+
+// recordRateLimit updates the rate-limit state from res's headers. The reset
+// header may be a Unix time or seconds from received.
+func (c *Client) recordRateLimit(res *resty.Response, received time.Time) {
+	if res == nil {
+		return
+	}
+	h := res.Header()
+	remaining, err := strconv.Atoi(firstHeader(h.Get("X-RateLimit-Remaining"), h.Get("RateLimit-Remaining")))
+	if err != nil {
+		return
+	}
+	var resetAt time.Time
+	if n, err := strconv.ParseInt(firstHeader(h.Get("X-RateLimit-Reset"), h.Get("RateLimit-Reset")), 10, 64); err == nil {
+		if n >= resetEpochThreshold {
+			resetAt = time.Unix(n, 0)
+		} else {
+			resetAt = received.Add(time.Duration(n) * time.Second)
+		}
+	}
+	c.mu.Lock()
+	c.rate = rateLimit{remaining: remaining, resetAt: resetAt, seen: true}
+	c.mu.Unlock()
+}
+
+// firstHeader returns the first non-empty value.
+func firstHeader(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+package truecoach
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// GetRawJSON performs an authenticated GET against path, relative to the API
+// base URL, and returns the response body undecoded.
+//
+// Unstable: this is an escape hatch for prototyping against endpoints the
+// package doesn't wrap yet. Prefer a typed method when one exists.
+func (c *Client) GetRawJSON(authToken, path string, params map[string]string) (json.RawMessage, error) {
+	return c.GetRawJSONContext(context.Background(), authToken, path, params)
+}
+
+// GetRawJSONContext is GetRawJSON with a context for cancellation and tracing.
+func (c *Client) GetRawJSONContext(ctx context.Context, authToken, path string, params map[string]string) (json.RawMessage, error) {
+	var out json.RawMessage
+	if err := c.doContext(ctx, http.MethodGet, authToken, path, params, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PostRawJSON performs an authenticated POST of body, encoded as JSON,
+// against path, relative to the API base URL, and returns the response body
+// undecoded. It goes through the same headers, error handling and retry
+// policy as typed methods; like other POSTs it is not retried, since the
+// write may not be idempotent.
+//
+// Unstable: this is an escape hatch for prototyping against endpoints the
+// package doesn't wrap yet. Prefer a typed method when one exists.
+func (c *Client) PostRawJSON(authToken, path string, body any) (json.RawMessage, error) {
+	return c.PostRawJSONContext(context.Background(), authToken, path, body)
+}
+
+// PostRawJSONContext is PostRawJSON with a context for cancellation and
+// tracing.
+func (c *Client) PostRawJSONContext(ctx context.Context, authToken, path string, body any) (json.RawMessage, error) {
+	var out json.RawMessage
+	if err := c.doContext(ctx, http.MethodPost, authToken, path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,20 @@
+package truecoach
+
+import "regexp"
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// RedactEmails replaces email addresses in body with "[redacted]". It is a
+// ready-made redactor for WithErrorRedactor.
+func RedactEmails(body string) string {
+	return emailPattern.ReplaceAllString(body, "[redacted]")
+}
+
+// WithErrorRedactor passes API error response bodies through redact before
+// they are put into returned errors, so PII doesn't end up in logs. Use
+// RedactEmails to mask email addresses. By default bodies are kept as-is.
+func WithErrorRedactor(redact func(body string) string) Option {
+	return func(c *Client) {
+		c.redact = redact
+	}
+}
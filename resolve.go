@@ -0,0 +1,161 @@
+package truecoach
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoUserID is returned by Client.ClientID when no user is logged in.
+var ErrNoUserID = errors.New("no user ID (log in first)")
+
+// WithUserIDResolution lets client-scoped methods such as GetHabitTrackers
+// take an empty clientID and fill in the logged-in user's own, as returned
+// by Client.ClientID.
+func WithUserIDResolution() Option {
+	return func(c *Client) {
+		c.resolveIDs = true
+	}
+}
+
+// ClientID returns the client ID of the user logged in with Login. The
+// mapping is remembered from any GetUserProfile call for that user;
+// otherwise the profile is fetched once with the stored token.
+func (c *Client) ClientID() (ClientID, error) {
+	return c.clientID(context.Background())
+}
+
+// clientID is ClientID with a context for the profile fetch.
+func (c *Client) clientID(ctx context.Context) (ClientID, error) {
+	c.mu.Lock()
+	userID := c.token.userID
+	id, ok := c.clientIDs[userID]
+	c.mu.Unlock()
+	if userID == "" {
+		return "", ErrNoUserID
+	}
+	if ok {
+		return id, nil
+	}
+	profile, err := c.GetUserProfileContext(ctx, "", userID)
+	if err != nil {
+		return "", err
+	}
+	return profile.User.ClientID, nil
+}
+
+// rememberClientID caches the client ID of userID.
+func (c *Client) rememberClientID(userID string, id ClientID) {
+	if id == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clientIDs == nil {
+		c.clientIDs = make(map[string]ClientID)
+	}
+	c.clientIDs[userID] = id
+}
+
+// resolveClientID returns clientID, or the logged-in user's client ID when
+// clientID is empty and WithUserIDResolution is set.
+func (c *Client) resolveClientID(ctx context.Context, clientID string) (string, error) {
+	if clientID != "" || !c.resolveIDs {
+		return clientID, nil
+	}
+	id, err := c.clientID(ctx)
+	return id.String(), err
+}
+
+// rememberProfile caches the time zone and units of the client whose
+// profile is p. A zone that is missing or invalid is skipped.
+func (c *Client) rememberProfile(p UserProfile) {
+	if p.ClientID == "" {
+		return
+	}
+	units := UnitsImperial
+	if p.metric() {
+		units = UnitsMetric
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clientUnits == nil {
+		c.clientUnits = make(map[string]Units)
+	}
+	c.clientUnits[p.ClientID.String()] = units
+	if p.Timezone == "" {
+		return
+	}
+	loc, err := p.Location()
+	if err != nil {
+		return
+	}
+	if c.zones == nil {
+		c.zones = make(map[string]*time.Location)
+	}
+	c.zones[p.ClientID.String()] = loc
+}
+
+// clientLocation returns the time zone to take clientID's days in, given a
+// time t passed by the caller. A t in a specific zone is kept as a deliberate
+// choice. For a t in UTC or Local, the zone of the client's profile is used
+// when it is known: seen in an earlier GetUserProfile for the client or, for
+// the logged-in user's own client, fetched once. Otherwise t's location is
+// kept.
+func (c *Client) clientLocation(ctx context.Context, clientID string, t time.Time) (*time.Location, error) {
+	if loc := t.Location(); loc != time.UTC && loc != time.Local {
+		return loc, nil
+	}
+	clientID, err := c.resolveClientID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	loc, ok := c.zones[clientID]
+	loggedIn := c.token.userID != ""
+	c.mu.Unlock()
+	if !ok && loggedIn {
+		c.fetchOwnProfile(ctx, "time zone")
+		c.mu.Lock()
+		loc, ok = c.zones[clientID]
+		c.mu.Unlock()
+	}
+	if !ok {
+		return t.Location(), nil
+	}
+	return loc, nil
+}
+
+// unitsFor returns the units clientID's habit values are in: those set by
+// WithResponseUnits or, without it, the units of the client's profile when
+// it is known, as for clientLocation. It returns "" if they aren't known.
+func (c *Client) unitsFor(ctx context.Context, clientID string) (Units, error) {
+	if c.units != "" {
+		return c.units, nil
+	}
+	clientID, err := c.resolveClientID(ctx, clientID)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	units, ok := c.clientUnits[clientID]
+	loggedIn := c.token.userID != ""
+	c.mu.Unlock()
+	if !ok && loggedIn {
+		c.fetchOwnProfile(ctx, "units")
+		c.mu.Lock()
+		units = c.clientUnits[clientID]
+		c.mu.Unlock()
+	}
+	return units, nil
+}
+
+// fetchOwnProfile looks up the logged-in user's client ID, which fetches
+// their profile if it hasn't been seen, so GetUserProfile remembers its
+// zone and units. A failed lookup only costs what was wanted, so it is
+// logged and the caller falls back to its default.
+func (c *Client) fetchOwnProfile(ctx context.Context, wanted string) {
+	if _, err := c.clientID(ctx); err != nil {
+		c.logger.Warn("truecoach: client "+wanted+" lookup failed", "error", err)
+	}
+}
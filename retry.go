@@ -0,0 +1,80 @@
+package truecoach
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"resty.dev/v3"
+)
+
+const (
+	retryBaseWait = 500 * time.Millisecond
+	retryMaxWait  = 30 * time.Second
+	// retryMaxShift bounds the exponent so the backoff can't overflow; the
+	// wait reaches retryMaxWait well before it.
+	retryMaxShift = 16
+)
+
+// WithRetries retries idempotent requests (GET, PUT, DELETE) up to n times
+// when they fail with a transport error, 429 or a 5xx status. Waits grow
+// exponentially from 500ms, capped at 30s, and follow Retry-After when the
+// server sends one. A cancelled context ends the wait at once. Login and
+// other POSTs are never retried.
+func WithRetries(n int) Option {
+	return func(c *Client) {
+		c.retries = n
+	}
+}
+
+// retryable reports whether a request with method that ended in res/err
+// should be tried again.
+func retryable(method string, res *resty.Response, err error) bool {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return res.StatusCode() == http.StatusTooManyRequests || res.StatusCode() >= 500
+}
+
+// retryWait returns how long to wait before retry number attempt (from 0).
+func retryWait(attempt int, res *resty.Response) time.Duration {
+	if res != nil {
+		if d := retryAfter(res); d > 0 {
+			return min(d, retryMaxWait)
+		}
+	}
+	return min(retryBaseWait<<min(attempt, retryMaxShift), retryMaxWait)
+}
+
+// sleepContext waits for d, returning ctx.Err() early if ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// executeWithRetry sends the request built by newReq, retrying per WithRetries.
+func (c *Client) executeWithRetry(ctx context.Context, newReq func() *resty.Request, method, path string) (*resty.Response, error) {
+	if c.transportErr != nil {
+		return nil, c.transportErr // retrying won't fix the configuration
+	}
+	for attempt := 0; ; attempt++ {
+		res, err := c.execute(newReq(), method, path)
+		if attempt >= c.retries || !retryable(method, res, err) {
+			return res, err
+		}
+		if err := sleepContext(ctx, retryWait(attempt, res)); err != nil {
+			return nil, err
+		}
+	}
+}
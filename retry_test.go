@@ -0,0 +1,39 @@
+package truecoach
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryCancelDuringBackoff(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		writeJSON(w, http.StatusServiceUnavailable, `{}`)
+	}, WithRetries(3))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	err := c.doContext(ctx, http.MethodGet, "token", "/anything", nil, nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("returned after %s, want well before the 30s Retry-After", elapsed)
+	}
+}
+
+func TestRetryWaitDoesNotOverflow(t *testing.T) {
+	for _, attempt := range []int{0, 5, 6, 34, 63, 100} {
+		d := retryWait(attempt, nil)
+		if d <= 0 || d > retryMaxWait {
+			t.Errorf("retryWait(%d) = %s, want in (0, %s]", attempt, d, retryMaxWait)
+		}
+	}
+	if d := retryWait(0, nil); d != retryBaseWait {
+		t.Errorf("retryWait(0) = %s, want %s", d, retryBaseWait)
+	}
+}
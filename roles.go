@@ -0,0 +1,100 @@
+package truecoach
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Role is the account role sent in the Role header.
+type Role string
+
+const (
+	RoleClient Role = "Client"
+	RoleCoach  Role = "Coach"
+)
+
+// ErrWrongRole is returned in strict role mode when an endpoint is known not
+// to accept the Client's role.
+var ErrWrongRole = errors.New("endpoint not available to this role")
+
+// roleRoute is a request method and path template.
+type roleRoute struct {
+	method   string
+	template string
+}
+
+// endpointRoles lists the roles that may make each known request. Requests
+// missing from the list are assumed to accept any role. Coaches can read a
+// client's habit trackers, but only the client logs them.
+var endpointRoles = map[roleRoute][]Role{
+	{http.MethodGet, PathUser}:          {RoleClient, RoleCoach},
+	{http.MethodGet, PathHabitTrackers}: {RoleClient, RoleCoach},
+	{http.MethodGet, PathHabitTracker}:  {RoleClient, RoleCoach},
+	{http.MethodPut, PathHabitTracker}:  {RoleClient},
+}
+
+// WithRole sets the account role sent in the Role header and checked by
+// WithStrictRole. The default is RoleClient.
+func WithRole(r Role) Option {
+	return func(c *Client) {
+		c.role = r
+	}
+}
+
+// accountRole returns the role set with WithRole, or the default.
+func (c *Client) accountRole() Role {
+	return cmp.Or(c.role, role)
+}
+
+// WithStrictRole checks each request against the known endpoint roles and
+// fails with ErrWrongRole before sending it when the Client's role can't
+// call the endpoint, instead of letting the API answer with a bare 403.
+// Off by default, since the list may lag behind the API.
+func WithStrictRole() Option {
+	return func(c *Client) {
+		c.strictRole = true
+	}
+}
+
+// checkRole enforces strict role mode for a method request to path.
+func (c *Client) checkRole(method, path string) error {
+	if !c.strictRole {
+		return nil
+	}
+	for route, roles := range endpointRoles {
+		if route.method != method || !matchesTemplate(route.template, path) {
+			continue
+		}
+		if slices.Contains(roles, c.accountRole()) {
+			return nil
+		}
+		return fmt.Errorf("%w: %s %s requires one of %v", ErrWrongRole, method, route.template, roles)
+	}
+	return nil
+}
+
+// matchesTemplate reports whether path fits a path template, treating each
+// {name} segment as a wildcard.
+func matchesTemplate(template, path string) bool {
+	t := strings.Split(template, "/")
+	p := strings.Split(path, "/")
+	if len(t) != len(p) {
+		return false
+	}
+	for i := range t {
+		if strings.HasPrefix(t[i], "{") && strings.HasSuffix(t[i], "}") {
+			if p[i] == "" {
+				return false
+			}
+			continue
+		}
+		if t[i] != p[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package truecoach
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestStrictRole(t *testing.T) {
+	var roles []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		roles = append(roles, r.Header.Get("Role"))
+		writeJSON(w, http.StatusOK, `{"id":1,"date":"2026-04-19"}`)
+	}
+	date, _ := ParseDate("2026-04-19")
+	update := HabitTrackingUpdateInput{Date: date, Steps: new(int)}
+
+	coach := newTestClient(t, handler, WithRole(RoleCoach), WithStrictRole())
+	if _, err := coach.UpdateHabitTracker("token", "5", "1", update); !errors.Is(err, ErrWrongRole) {
+		t.Errorf("coach update: err = %v, want ErrWrongRole", err)
+	}
+	if len(roles) != 0 {
+		t.Errorf("coach update was sent")
+	}
+	if _, err := coach.GetUserProfile("token", "7"); err != nil {
+		t.Errorf("coach profile: %v", err)
+	}
+	if _, err := coach.GetRawJSON("token", "/clients/5/habit_trackers/1", nil); err != nil {
+		t.Errorf("coach read of a tracker: %v", err)
+	}
+
+	client := newTestClient(t, handler, WithStrictRole())
+	if _, err := client.UpdateHabitTracker("token", "5", "1", update); err != nil {
+		t.Errorf("client update: %v", err)
+	}
+
+	lax := newTestClient(t, handler, WithRole(RoleCoach))
+	if _, err := lax.UpdateHabitTracker("token", "5", "1", update); err != nil {
+		t.Errorf("coach update without strict mode: %v", err)
+	}
+
+	want := []string{"Coach", "Coach", "Client", "Coach"}
+	if !slices.Equal(roles, want) {
+		t.Errorf("Role headers %v, want %v", roles, want)
+	}
+}
@@ -0,0 +1,148 @@
+package truecoach
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"resty.dev/v3"
+)
+
+// latencyBuckets are the upper bounds of the latency histogram. Slower
+// requests land in a final unbounded bucket.
+var latencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// Stats is a snapshot of the built-in request metrics.
+type Stats struct {
+	// Requests counts requests by endpoint, e.g. "GET /users/:id".
+	Requests map[string]int
+	// Errors counts failed requests by class: "4xx", "5xx" or "transport".
+	Errors map[string]int
+	// Latency is the request latency histogram.
+	Latency []LatencyBucket
+}
+
+// LatencyBucket counts requests that took at most UpperBound. The last
+// bucket has a zero UpperBound and counts everything slower.
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count      int
+}
+
+// statsCollector accumulates request metrics. A nil *statsCollector is valid
+// and records nothing.
+type statsCollector struct {
+	mu       sync.Mutex
+	requests map[string]int
+	errors   map[string]int
+	latency  []int
+}
+
+func newStatsCollector() *statsCollector {
+	s := &statsCollector{}
+	s.reset()
+	return s
+}
+
+// reset clears all counters. The caller must hold s.mu or own s exclusively.
+func (s *statsCollector) reset() {
+	s.requests = make(map[string]int)
+	s.errors = make(map[string]int)
+	s.latency = make([]int, len(latencyBuckets)+1)
+}
+
+func (s *statsCollector) record(method, path string, res *resty.Response, err error, elapsed time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[method+" "+endpointKey(path)]++
+	switch {
+	case err != nil:
+		s.errors["transport"]++
+	case res.StatusCode() >= 500:
+		s.errors["5xx"]++
+	case res.StatusCode() >= 400:
+		s.errors["4xx"]++
+	}
+	i := 0
+	for i < len(latencyBuckets) && elapsed > latencyBuckets[i] {
+		i++
+	}
+	s.latency[i]++
+}
+
+func (s *statsCollector) snapshot() Stats {
+	if s == nil {
+		return Stats{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := Stats{
+		Requests: make(map[string]int, len(s.requests)),
+		Errors:   make(map[string]int, len(s.errors)),
+		Latency:  make([]LatencyBucket, len(s.latency)),
+	}
+	for k, v := range s.requests {
+		st.Requests[k] = v
+	}
+	for k, v := range s.errors {
+		st.Errors[k] = v
+	}
+	for i, n := range s.latency {
+		if i < len(latencyBuckets) {
+			st.Latency[i].UpperBound = latencyBuckets[i]
+		}
+		st.Latency[i].Count = n
+	}
+	return st
+}
+
+// endpointKey strips the host and query from path and replaces numeric
+// segments with ":id", so requests for different IDs share one counter.
+func endpointKey(path string) string {
+	if u, err := url.Parse(path); err == nil {
+		path = u.Path
+	}
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		if seg != "" && strings.Trim(seg, "0123456789") == "" {
+			segs[i] = ":id"
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+// WithMetricsCollector turns on the built-in request metrics, read with
+// Client.Stats.
+func WithMetricsCollector() Option {
+	return func(c *Client) {
+		c.stats = newStatsCollector()
+	}
+}
+
+// Stats returns a snapshot of the request metrics. It is empty unless
+// WithMetricsCollector is set.
+func (c *Client) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// ResetStats clears the request metrics.
+func (c *Client) ResetStats() {
+	if c.stats == nil {
+		return
+	}
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+	c.stats.reset()
+}
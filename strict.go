@@ -0,0 +1,87 @@
+package truecoach
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrUnexpectedNull is wrapped by the error returned in strict null mode when
+// the API sends null for a field that can't represent it.
+var ErrUnexpectedNull = errors.New("unexpected null")
+
+// WithStrictNulls makes decoding fail when a response has null for a
+// non-pointer number, string or boolean field, such as
+// HabitTrackerTracking.ID or ClientID, instead of silently leaving it zero.
+// The error is a *DecodeError wrapping ErrUnexpectedNull, with FieldPath
+// naming the field, e.g. "trackings[2].client_id". Pointer fields, slices
+// and types that decode themselves, such as NullableFloat, Bool and
+// ClientID, accept null as usual.
+func WithStrictNulls() Option {
+	return func(c *Client) {
+		c.strictNulls = true
+	}
+}
+
+var unmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+
+// findNull walks data alongside type t and returns the path of the first
+// null it finds in a field that would decode it as a zero value. path is
+// the location of data. It reports false if there is none.
+func findNull(data json.RawMessage, t reflect.Type, path string) (string, bool) {
+	if t.Kind() == reflect.Pointer {
+		if string(data) == "null" {
+			return "", false
+		}
+		return findNull(data, t.Elem(), path)
+	}
+	custom := reflect.PointerTo(t).Implements(unmarshalerType)
+	if string(data) == "null" {
+		if custom {
+			return "", false
+		}
+		switch t.Kind() {
+		case reflect.Bool, reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return strings.TrimPrefix(path, "."), true
+		}
+		return "", false
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(data, &fields) != nil {
+			return "", false // not an object; the type decodes it itself
+		}
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name, ok := jsonFieldName(f)
+			if !ok {
+				continue
+			}
+			if v, ok := fields[name]; ok {
+				if p, ok := findNull(v, f.Type, path+"."+name); ok {
+					return p, true
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if custom {
+			return "", false
+		}
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return "", false
+		}
+		for i, item := range items {
+			if p, ok := findNull(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i)); ok {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
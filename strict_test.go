@@ -0,0 +1,59 @@
+package truecoach
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestStrictNullsFieldPath(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"response":{"trackings":[{"id":1,"client_id":5},{"id":2,"client_id":null}]}}`)
+	}, WithStrictNulls())
+	_, err := c.GetHabitTrackers("token", "5", Today())
+	var de *DecodeError
+	if !errors.As(err, &de) || !errors.Is(err, ErrUnexpectedNull) {
+		t.Fatalf("err = %v, want a *DecodeError wrapping ErrUnexpectedNull", err)
+	}
+	if de.FieldPath != "trackings[1].client_id" {
+		t.Errorf("FieldPath = %q, want trackings[1].client_id", de.FieldPath)
+	}
+}
+
+func TestFindNull(t *testing.T) {
+	type entry struct {
+		ID       int           `json:"id"`
+		Name     string        `json:"name"`
+		Steps    *int          `json:"steps"`
+		Weight   NullableFloat `json:"weight"`
+		Done     Bool          `json:"done"`
+		ClientID ClientID      `json:"client_id"`
+		Tags     []string      `json:"tags"`
+		Skipped  int           `json:"-"`
+	}
+	tests := []struct {
+		name string
+		data string
+		want string // "" if the nulls are all accepted
+	}{
+		{"pointer", `{"steps":null}`, ""},
+		{"NullableFloat", `{"weight":null}`, ""},
+		{"Bool", `{"done":null}`, ""},
+		{"ClientID", `{"client_id":null}`, ""},
+		{"slice", `{"tags":null}`, ""},
+		{"ignored field", `{"Skipped":null,"-":null}`, ""},
+		{"int", `{"steps":1,"id":null}`, "id"},
+		{"string", `{"name":null}`, "name"},
+		{"slice element", `{"tags":["a",null]}`, "tags[1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := findNull(json.RawMessage(tt.data), reflect.TypeFor[entry](), "")
+			if ok != (tt.want != "") || got != tt.want {
+				t.Errorf("findNull = %q, %v; want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
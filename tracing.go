@@ -0,0 +1,20 @@
+package truecoach
+
+import "context"
+
+// RequestTracer wraps each API request in a span. StartRequest receives the
+// request's context, so spans nest under the caller's, and returns the
+// context to send the request with plus a function to call when it is done.
+//
+// Build with the otel tag for an OpenTelemetry implementation, WithOTelTracing.
+type RequestTracer interface {
+	StartRequest(ctx context.Context, method, endpoint string) (context.Context, func(status int, err error))
+}
+
+// WithRequestTracer traces every request with t. The endpoint passed to t
+// has numeric IDs replaced, e.g. "/users/:id".
+func WithRequestTracer(t RequestTracer) Option {
+	return func(c *Client) {
+		c.tracer = t
+	}
+}
@@ -0,0 +1,46 @@
+//go:build otel
+
+package truecoach
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithOTelTracing wraps each request in an OpenTelemetry client span from
+// tracer, with the method, endpoint and response status as attributes.
+// Only available when built with the otel tag.
+func WithOTelTracing(tracer trace.Tracer) Option {
+	return WithRequestTracer(otelTracer{tracer})
+}
+
+type otelTracer struct {
+	tracer trace.Tracer
+}
+
+func (o otelTracer) StartRequest(ctx context.Context, method, endpoint string) (context.Context, func(int, error)) {
+	ctx, span := o.tracer.Start(ctx, method+" "+endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("truecoach.endpoint", endpoint),
+		),
+	)
+	return ctx, func(status int, err error) {
+		if status != 0 {
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+		}
+		switch {
+		case err != nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case status >= 400:
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		span.End()
+	}
+}
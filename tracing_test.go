@@ -0,0 +1,84 @@
+package truecoach
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type parentKey struct{}
+
+// recordingTracer records the parent value found in each request's context.
+type recordingTracer struct {
+	mu      sync.Mutex
+	parents []any
+}
+
+func (r *recordingTracer) StartRequest(ctx context.Context, method, endpoint string) (context.Context, func(int, error)) {
+	r.mu.Lock()
+	r.parents = append(r.parents, ctx.Value(parentKey{}))
+	r.mu.Unlock()
+	return ctx, func(int, error) {}
+}
+
+func TestContextVariantsPropagateSpans(t *testing.T) {
+	tracer := &recordingTracer{}
+	c := newTestClient(t, habitServer(t, map[string]string{
+		"2026-04-19": `{"id":1,"steps":1000}`,
+		"2026-04-20": `{"id":2,"steps":2000}`,
+	}), WithRequestTracer(tracer))
+	ctx := context.WithValue(context.Background(), parentKey{}, "span")
+
+	asOf := time.Date(2026, 4, 20, 12, 0, 0, 0, time.UTC)
+	if _, err := c.GetLoggingStreakContext(ctx, "token", "5", asOf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetHabitTrackersContext(ctx, "token", "5", NewDate(asOf)); err != nil {
+		t.Fatal(err)
+	}
+	if len(tracer.parents) == 0 {
+		t.Fatal("no requests traced")
+	}
+	for i, p := range tracer.parents {
+		if p != "span" {
+			t.Errorf("request %d started without the caller's context", i)
+		}
+	}
+}
+
+func TestAuthContextVariantsPropagateSpans(t *testing.T) {
+	tracer := &recordingTracer{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case PathOAuthToken:
+			writeJSON(w, http.StatusOK, `{"access_token":"a","refresh_token":"r","user_id":1}`)
+		default:
+			w.Header().Set("Date", time.Now().UTC().Format(http.TimeFormat))
+			writeJSON(w, http.StatusNotFound, `{}`)
+		}
+	}, WithRequestTracer(tracer))
+	ctx := context.WithValue(context.Background(), parentKey{}, "span")
+
+	if _, err := c.DiscoverOAuthContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.LoginContext(ctx, "me@example.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.RefreshContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetServerTimeContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(tracer.parents); n != 4 {
+		t.Fatalf("%d requests traced, want 4", n)
+	}
+	for i, p := range tracer.parents {
+		if p != "span" {
+			t.Errorf("request %d started without the caller's context", i)
+		}
+	}
+}
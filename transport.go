@@ -0,0 +1,106 @@
+package truecoach
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WithMaxIdleConnsPerHost sets how many idle keep-alive connections the
+// transport keeps per host. Raise it when one Client serves many concurrent
+// requests. When unset, the transport keeps its default (for resty's own
+// transport, GOMAXPROCS+1).
+func WithMaxIdleConnsPerHost(n int) Option {
+	return withTransport(func(t *http.Transport) {
+		t.MaxIdleConnsPerHost = n
+	})
+}
+
+// WithIdleConnTimeout sets how long an idle connection stays in the pool
+// before it is closed. When unset, the transport keeps its default (90s for
+// resty's own transport).
+func WithIdleConnTimeout(d time.Duration) Option {
+	return withTransport(func(t *http.Transport) {
+		t.IdleConnTimeout = d
+	})
+}
+
+// WithHTTP2 turns HTTP/2 on or off. With false, requests use HTTP/1.1 only,
+// which some serverless platforms and proxies handle better. When unset,
+// the transport keeps its default (resty's own transport attempts HTTP/2).
+func WithHTTP2(enabled bool) Option {
+	return withTransport(func(t *http.Transport) {
+		t.ForceAttemptHTTP2 = enabled
+		if enabled {
+			t.TLSNextProto = nil
+		} else {
+			// A non-nil empty map is how net/http is told not to upgrade.
+			t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+		}
+	})
+}
+
+// WithDisableKeepAlive, given true, closes each connection after one request
+// instead of pooling it. When unset, keep-alive stays on.
+func WithDisableKeepAlive(disable bool) Option {
+	return withTransport(func(t *http.Transport) {
+		t.DisableKeepAlives = disable
+	})
+}
+
+// WithInsecureSkipVerify turns off TLS certificate verification.
+//
+// DANGER: this lets anyone on the network read and alter traffic, including
+// passwords and tokens. It exists only for tests against a local mock with
+// a self-signed certificate. Never use it against the real API. A warning is
+// logged whenever a Client is created with it.
+func WithInsecureSkipVerify() Option {
+	return func(c *Client) {
+		c.insecureTLS = true
+		withTransport(func(t *http.Transport) {
+			if t.TLSClientConfig == nil {
+				t.TLSClientConfig = &tls.Config{}
+			}
+			t.TLSClientConfig.InsecureSkipVerify = true
+		})(c)
+	}
+}
+
+// withTransport queues a change to the underlying *http.Transport, applied
+// once NewClient has settled on the resty client.
+func withTransport(fn func(*http.Transport)) Option {
+	return func(c *Client) {
+		c.transportOpts = append(c.transportOpts, fn)
+	}
+}
+
+// applyTransportOpts runs the queued transport changes on a copy of the
+// resty client's transport and installs the copy, so a transport shared with
+// other clients, such as http.DefaultTransport, is left alone. A nil
+// transport, as in an http.Client built without one, stands for
+// http.DefaultTransport. When the transport is not an *http.Transport the
+// changes can't be made; every request then fails with the reason instead
+// of running without them.
+func (c *Client) applyTransportOpts() {
+	if len(c.transportOpts) == 0 {
+		return
+	}
+	if c.httpClient.Transport() == nil {
+		c.httpClient.SetTransport(http.DefaultTransport)
+	}
+	t, err := c.httpClient.HTTPTransport()
+	if err != nil {
+		c.transportErr = fmt.Errorf("transport options not applied: %w", err)
+		c.logger.Error("truecoach: transport options not applied", "error", err)
+		return
+	}
+	t = t.Clone()
+	for _, fn := range c.transportOpts {
+		fn(t)
+	}
+	c.httpClient.SetTransport(t)
+	if c.insecureTLS {
+		c.logger.Warn("truecoach: TLS certificate verification is disabled (WithInsecureSkipVerify)")
+	}
+}
@@ -0,0 +1,75 @@
+package truecoach
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"resty.dev/v3"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestTransportOptionsLeaveSharedTransport(t *testing.T) {
+	shared := &http.Transport{}
+	hc := &http.Client{Transport: shared}
+	c := NewClient(WithHTTPClient(hc), WithInsecureSkipVerify(), WithMaxIdleConnsPerHost(50))
+	defer c.Close()
+	if hc.Transport != shared {
+		t.Error("WithHTTPClient's client had its transport replaced")
+	}
+	if (shared.TLSClientConfig != nil && shared.TLSClientConfig.InsecureSkipVerify) || shared.MaxIdleConnsPerHost != 0 {
+		t.Error("shared transport was modified")
+	}
+	got, err := c.httpClient.HTTPTransport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == shared || !got.TLSClientConfig.InsecureSkipVerify || got.MaxIdleConnsPerHost != 50 {
+		t.Error("options not applied to the Client's own transport")
+	}
+}
+
+func TestTransportOptionsUnsupportedTransport(t *testing.T) {
+	called := false
+	hc := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		called = true
+		return nil, errors.New("unreachable")
+	})}
+	c := NewClient(WithHTTPClient(hc), WithHTTP2(false), WithRetries(2))
+	defer c.Close()
+	_, err := c.GetUserProfile("token", "7")
+	if !errors.Is(err, resty.ErrNotHttpTransportType) {
+		t.Errorf("err = %v, want ErrNotHttpTransportType", err)
+	}
+	if called {
+		t.Error("request sent without the transport options")
+	}
+}
+
+func TestTransportOptionsNilTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"user":{"id":7}}`)
+	}))
+	defer srv.Close()
+	hc := &http.Client{Timeout: 5 * time.Second}
+	c := NewClient(WithHTTPClient(hc), WithBaseURL(srv.URL), WithMaxIdleConnsPerHost(50), WithHTTP2(false))
+	defer c.Close()
+	if _, err := c.GetUserProfile("token", "7"); err != nil {
+		t.Fatalf("request with a nil-Transport client: %v", err)
+	}
+	if hc.Transport != nil {
+		t.Error("WithHTTPClient's client had its transport set")
+	}
+	if d := http.DefaultTransport.(*http.Transport); d.MaxIdleConnsPerHost == 50 {
+		t.Error("http.DefaultTransport was modified")
+	}
+	got, err := c.httpClient.HTTPTransport()
+	if err != nil || got == http.DefaultTransport || got.MaxIdleConnsPerHost != 50 {
+		t.Errorf("options not applied to a copy of the default transport")
+	}
+}
@@ -0,0 +1,66 @@
+package truecoach
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// MetricPoint is one day's value of a metric with its moving average.
+type MetricPoint struct {
+	Date  time.Time
+	Value float64
+	// Average is the mean of the values logged in the window of days ending
+	// on Date. Days without a value are skipped, not counted as zero.
+	Average float64
+}
+
+// GetWeightTrend returns the weights logged from through to (inclusive), each
+// with a window-day moving average. Only days with a logged weight produce a
+// point. Each average covers the calendar days Date-window+1 through Date
+// and uses whatever weights fall in that span, so gaps shrink the sample
+// rather than pulling the average down.
+func (c *Client) GetWeightTrend(authToken, clientID string, from, to time.Time, window int) ([]MetricPoint, error) {
+	return c.GetWeightTrendContext(context.Background(), authToken, clientID, from, to, window)
+}
+
+// GetWeightTrendContext is GetWeightTrend with a context for cancellation and
+// tracing.
+func (c *Client) GetWeightTrendContext(ctx context.Context, authToken, clientID string, from, to time.Time, window int) ([]MetricPoint, error) {
+	if window < 1 {
+		return nil, errors.New("moving average window must be at least 1 day")
+	}
+	// Fetch window-1 extra days so the first points get full windows.
+	var points []MetricPoint
+	start := from.AddDate(0, 0, -(window - 1))
+	err := c.StreamHabitTrackers(ctx, authToken, clientID, start, to, func(t HabitTrackerTracking) error {
+		if t.Weight.Valid {
+			points = append(points, MetricPoint{Date: t.Date.Time, Value: t.Weight.Value})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	movingAverage(points, window)
+	first := dayOf(from).Time
+	for len(points) > 0 && points[0].Date.Before(first) {
+		points = points[1:]
+	}
+	return points, nil
+}
+
+// movingAverage fills in Average for date-ordered points over a trailing
+// window of calendar days.
+func movingAverage(points []MetricPoint, window int) {
+	lo, sum := 0, 0.0
+	for i := range points {
+		sum += points[i].Value
+		earliest := points[i].Date.AddDate(0, 0, -(window - 1))
+		for points[lo].Date.Before(earliest) {
+			sum -= points[lo].Value
+			lo++
+		}
+		points[i].Average = sum / float64(i-lo+1)
+	}
+}
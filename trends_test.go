@@ -0,0 +1,64 @@
+package truecoach
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetWeightTrend(t *testing.T) {
+	c := newTestClient(t, habitServer(t, map[string]string{
+		"2026-04-01": `{"id":1,"weight":80}`, // before from, only warms up
+		"2026-04-03": `{"id":3,"weight":82}`,
+		"2026-04-04": `{"id":4,"steps":5000}`, // logged, but no weight
+		"2026-04-05": `{"id":5,"weight":84}`,
+		"2026-04-06": `{"id":6,"weight":86}`,
+	}))
+	day := func(d int) time.Time { return time.Date(2026, 4, d, 0, 0, 0, 0, time.UTC) }
+	from, to := day(3), day(6)
+
+	tests := []struct {
+		name   string
+		window int
+		want   []MetricPoint
+	}{
+		{
+			name:   "window of 1",
+			window: 1,
+			want: []MetricPoint{
+				{Date: day(3), Value: 82, Average: 82},
+				{Date: day(5), Value: 84, Average: 84},
+				{Date: day(6), Value: 86, Average: 86},
+			},
+		},
+		{
+			name:   "gaps shrink the window",
+			window: 3,
+			want: []MetricPoint{
+				{Date: day(3), Value: 82, Average: 81}, // with the warm-up day
+				{Date: day(5), Value: 84, Average: 83},
+				{Date: day(6), Value: 86, Average: 85},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetWeightTrend("token", "5", from, to, tt.window)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d points %+v, want %d", len(got), got, len(tt.want))
+			}
+			for i, p := range got {
+				w := tt.want[i]
+				if !p.Date.Equal(w.Date) || p.Value != w.Value || p.Average != w.Average {
+					t.Errorf("point %d = %+v, want %+v", i, p, w)
+				}
+			}
+		})
+	}
+
+	if _, err := c.GetWeightTrend("token", "5", from, to, 0); err == nil {
+		t.Error("window of 0 accepted")
+	}
+}
@@ -1,9 +1,15 @@
 package truecoach
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strconv"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"resty.dev/v3"
@@ -69,34 +75,235 @@ const (
 	userAgent      = "okhttp/4.12.0"
 	accept         = "application/json"
 	contentType    = "application/json; charset=utf-8"
-	role           = "Client"
+	role           = RoleClient
 	acceptEncoding = "gzip"
 )
 
 // Client represents a TrueCoach API client.
 type Client struct {
 	httpClient *resty.Client
+	clientConfig
+
+	mu    sync.Mutex
+	token tokenState
+	oauth *OAuthMetadata
+	skew  time.Duration
+	rate  rateLimit
+	// clientIDs maps user IDs to client IDs seen in profiles.
+	clientIDs map[string]ClientID
+	// zones maps client IDs to the time zones of their profiles.
+	zones map[string]*time.Location
+	// clientUnits maps client IDs to the units of their profiles.
+	clientUnits map[string]Units
+
+	refreshMu    sync.Mutex
+	tokenChanged chan struct{}
+	stop         chan struct{}
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+// clientConfig holds the settings made by options. They don't change after
+// NewClient returns, so Clone copies them as-is.
+type clientConfig struct {
+	cache       *responseCache
+	stats       *statsCollector
+	tracer      RequestTracer
+	logger      *slog.Logger
+	units       Units
+	apiVersion  string
+	strictNulls bool
+	strictRole  bool
+	role        Role
+	redact      func(body string) string
+	refreshLead time.Duration
+	retries     int
+	insecureTLS bool
+	resolveIDs  bool
+	validate    bool
+	dateHeader  bool
+	clock       func() time.Time
+	hook        func(path string, body []byte) ([]byte, error)
+	baseURL     string
+	userAgent   string
+	timeout     time.Duration
+
+	transportOpts []func(*http.Transport)
+	transportErr  error // why transportOpts couldn't be applied
+}
+
+// APIError is returned when the API rejects a request with a non-2xx status.
+// Use errors.As to tell it apart from transport failures.
+type APIError struct {
+	StatusCode int
+	// Message is the error the server gave, e.g. "invalid_grant: ..." from
+	// the OAuth error and error_description fields, or a message field. It
+	// is empty if the body had none.
+	Message string
+	// Body is the response body.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Body)
 }
 
-// checkStatus returns an error if the HTTP response indicates failure.
-func checkStatus(res *resty.Response) error {
+// checkStatus returns an *APIError if the HTTP response indicates failure.
+// The body and message in the error pass through the configured redactor.
+func (c *Client) checkStatus(res *resty.Response) error {
 	if res.IsSuccess() {
 		return nil
 	}
-	return fmt.Errorf("API error %d: %s", res.StatusCode(), res.String())
+	body := res.String()
+	var parsed struct {
+		Error            any    `json:"error"`
+		ErrorDescription string `json:"error_description"`
+		Message          string `json:"message"`
+	}
+	json.Unmarshal(res.Bytes(), &parsed) // best effort; the body may not be JSON
+	code, _ := parsed.Error.(string)     // some endpoints nest an object here
+	message := cmp.Or(parsed.ErrorDescription, parsed.Message)
+	if code != "" && message != "" {
+		message = code + ": " + message
+	} else if code != "" {
+		message = code
+	}
+	if c.redact != nil {
+		body = c.redact(body)
+		message = c.redact(message)
+	}
+	return &APIError{StatusCode: res.StatusCode(), Message: message, Body: []byte(body)}
+}
+
+// doContext sends an authenticated request and decodes the JSON response
+// into out. ctx cancels the request and carries the caller's span. An empty
+// authToken uses the token stored by Login, refreshed first when it is about
+// to expire and once more if the server rejects it with 401; see
+// refreshIfExpiring. GET responses go through the response cache when it is
+// enabled; any other method invalidates it, since the write may change what
+// a cached read returns.
+func (c *Client) doContext(ctx context.Context, method, authToken, path string, params map[string]string, body, out any) error {
+	if err := c.checkRole(method, path); err != nil {
+		return err
+	}
+	stored := authToken == "" || authToken == c.accessToken()
+	if stored {
+		if err := c.refreshIfExpiring(ctx); err != nil {
+			// The token may still work for a few seconds; a 401 retries below.
+			c.logger.Warn("truecoach: token refresh failed", "error", err)
+		}
+		authToken = c.accessToken()
+	}
+	if authToken == "" {
+		return ErrNoToken
+	}
+	key := cacheKey(method, authToken, path, params)
+	if method == http.MethodGet && !isFreshRead(ctx) {
+		if data, ok := c.cache.get(key); ok {
+			return c.decodeResponse(path, data, out)
+		}
+	}
+	newReq := func() *resty.Request {
+		req := c.httpClient.R().
+			SetContext(ctx).
+			SetHeader("Authorization", "Bearer "+authToken).
+			SetQueryParams(params)
+		if body != nil {
+			req.SetBody(body)
+		}
+		return req
+	}
+	res, err := c.executeWithRetry(ctx, newReq, method, path)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode() == http.StatusUnauthorized && stored && c.refreshAfterRejection(ctx, authToken) {
+		authToken = c.accessToken()
+		if res, err = c.executeWithRetry(ctx, newReq, method, path); err != nil {
+			return err
+		}
+	}
+	if err := c.checkStatus(res); err != nil {
+		return err
+	}
+	if err := c.checkContentType(res); err != nil {
+		return err
+	}
+	data := res.Bytes()
+	if method == http.MethodGet {
+		c.cache.set(key, data)
+	} else {
+		c.cache.clear()
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return c.decodeResponse(path, data, out)
+}
+
+// execute sends req, wrapped in a tracing span when a RequestTracer is set,
+// records it in the request metrics and updates the clock skew.
+func (c *Client) execute(req *resty.Request, method, path string) (*resty.Response, error) {
+	if c.transportErr != nil {
+		return nil, c.transportErr
+	}
+	var end func(status int, err error)
+	if c.tracer != nil {
+		var ctx context.Context
+		ctx, end = c.tracer.StartRequest(req.Context(), method, endpointKey(path))
+		req.SetContext(ctx)
+	}
+	c.setDateHeader(req)
+	start := time.Now()
+	res, err := req.Execute(method, path)
+	c.stats.record(method, path, res, err, time.Since(start))
+	if err == nil {
+		c.recordSkew(res, start)
+		c.recordRateLimit(res, time.Now())
+	}
+	if end != nil {
+		status := 0
+		if res != nil {
+			status = res.StatusCode()
+		}
+		end(status, err)
+	}
+	return res, err
 }
 
 // NewClient returns a new TrueCoach API client with standard request headers set.
-func NewClient() *Client {
-	return &Client{
-		httpClient: resty.New().
-			SetBaseURL(apiBaseURL).
-			SetHeader("User-Agent", userAgent).
-			SetHeader("Accept", accept).
-			SetHeader("Content-Type", contentType).
-			SetHeader("Role", role).
-			SetHeader("Accept-Encoding", acceptEncoding),
+// Call Login, or SetAccessToken with a saved token, before using
+// authenticated endpoints; methods then use the stored token when given an
+// empty authToken.
+func NewClient(opts ...Option) *Client {
+	c := &Client{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.httpClient == nil {
+		c.httpClient = resty.New()
 	}
+	if c.logger == nil {
+		c.logger = slog.New(slog.DiscardHandler)
+	}
+	c.httpClient.
+		SetBaseURL(cmp.Or(c.baseURL, apiBaseURL)).
+		SetHeader("User-Agent", cmp.Or(c.userAgent, userAgent)).
+		SetHeader("Accept", c.acceptHeader()).
+		SetHeader("Content-Type", contentType).
+		SetHeader("Role", string(c.accountRole())).
+		SetHeader("Accept-Encoding", acceptEncoding)
+	if c.timeout > 0 {
+		c.httpClient.SetTimeout(c.timeout)
+	}
+	c.applyTransportOpts()
+	if c.refreshLead > 0 {
+		c.startRefreshLoop()
+	}
+	return c
 }
 
 // ClientID is the user/client ID. The API sometimes returns it as a number;
@@ -106,14 +313,14 @@ type ClientID string
 // UnmarshalJSON accepts either a JSON number or string for the client ID.
 func (c *ClientID) UnmarshalJSON(data []byte) error {
 	var v any
-	if err := json.Unmarshal(data, &v); err != nil {
+	if err := unmarshalUseNumber(data, &v); err != nil {
 		return err
 	}
 	switch v := v.(type) {
 	case string:
 		*c = ClientID(v)
-	case float64:
-		*c = ClientID(strconv.FormatFloat(v, 'f', 0, 64))
+	case json.Number:
+		*c = ClientID(v.String())
 	default:
 		return fmt.Errorf("user_id: expected string or number, got %T", v)
 	}
@@ -124,28 +331,27 @@ func (c *ClientID) UnmarshalJSON(data []byte) error {
 func (c ClientID) String() string { return string(c) }
 
 type TokenResponse struct {
-	AccessToken string   `json:"access_token"`
-	TokenType   string   `json:"token_type"`
-	UserID      ClientID `json:"user_id"`
+	AccessToken  string   `json:"access_token"`
+	TokenType    string   `json:"token_type"`
+	UserID       ClientID `json:"user_id"`
+	RefreshToken string   `json:"refresh_token"`
+	// ExpiresIn is the access token lifetime in seconds.
+	ExpiresIn int `json:"expires_in"`
 }
 
+// Login authenticates with email and password. The returned token is also
+// stored on the Client, so later calls may pass an empty authToken.
 func (c *Client) Login(email, password string) (*TokenResponse, error) {
-	var out TokenResponse
-	res, err := c.httpClient.R().
-		SetBody(map[string]string{
-			"grant_type": "password",
-			"username":   email,
-			"password":   password,
-		}).
-		SetResult(&out).
-		Post("/oauth/token")
-	if err != nil {
-		return nil, err
-	}
-	if err := checkStatus(res); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return c.LoginContext(context.Background(), email, password)
+}
+
+// LoginContext is Login with a context for cancellation and tracing.
+func (c *Client) LoginContext(ctx context.Context, email, password string) (*TokenResponse, error) {
+	return c.requestToken(ctx, map[string]string{
+		"grant_type": "password",
+		"username":   email,
+		"password":   password,
+	})
 }
 
 // UserProfile is the "user" object returned by the user profile endpoint.
@@ -163,79 +369,271 @@ type UserProfile struct {
 	ImageID   *int     `json:"image_id"`
 }
 
+// Units is a measurement system as named by the API's units field.
+type Units string
+
+const (
+	UnitsImperial Units = "imperial"
+	UnitsMetric   Units = "metric"
+)
+
+const cmPerInch = 2.54
+
+// metric reports whether the profile uses metric units. Accounts with no
+// Units set are treated as imperial, the app's default.
+func (p UserProfile) metric() bool {
+	return Units(p.Units) == UnitsMetric
+}
+
+// HeightCm returns the height in centimeters, or nil if no height is set.
+// Height is stored in centimeters on metric accounts and inches otherwise.
+func (p UserProfile) HeightCm() *float64 {
+	if p.Height == nil {
+		return nil
+	}
+	h := float64(*p.Height)
+	if !p.metric() {
+		h *= cmPerInch
+	}
+	return &h
+}
+
+// HeightInches returns the height in inches, or nil if no height is set.
+// Height is stored in centimeters on metric accounts and inches otherwise.
+func (p UserProfile) HeightInches() *float64 {
+	if p.Height == nil {
+		return nil
+	}
+	h := float64(*p.Height)
+	if p.metric() {
+		h /= cmPerInch
+	}
+	return &h
+}
+
 // UserProfileResponse is the response from GET /users/{userID}.
 // The API returns a large payload; we decode the "user" object used for client_id lookup.
 type UserProfileResponse struct {
 	User UserProfile `json:"user"`
+	// Raw is the full response body, for decoding sections not modeled here.
+	Raw json.RawMessage `json:"-"`
 }
 
 // GetUserProfile fetches the user profile for the given user ID.
 // Use the ClientID from the response (profile.User.ClientID) for client-scoped endpoints like habit trackers.
 func (c *Client) GetUserProfile(authToken string, userID string) (*UserProfileResponse, error) {
-	var out UserProfileResponse
-	res, err := c.httpClient.R().
-		SetHeader("Authorization", "Bearer "+authToken).
-		SetResult(&out).
-		Get("/users/" + userID)
+	return c.GetUserProfileContext(context.Background(), authToken, userID)
+}
+
+// GetUserProfileContext is GetUserProfile with a context for cancellation and
+// tracing.
+func (c *Client) GetUserProfileContext(ctx context.Context, authToken string, userID string) (*UserProfileResponse, error) {
+	var raw json.RawMessage
+	path, err := endpoint(PathUser, "userID", userID)
 	if err != nil {
 		return nil, err
 	}
-	if err := checkStatus(res); err != nil {
+	if err := c.doContext(ctx, http.MethodGet, authToken, path, c.unitsParams(nil), nil, &raw); err != nil {
+		return nil, err
+	}
+	var out UserProfileResponse
+	if err := c.decode(path, raw, &out); err != nil {
 		return nil, err
 	}
+	out.Raw = raw
+	c.rememberClientID(userID, out.User.ClientID)
+	c.rememberProfile(out.User)
 	return &out, nil
 }
 
+// Profile is GetUserProfile with the token stored by Login or SetAccessToken.
+func (c *Client) Profile(userID string) (*UserProfileResponse, error) {
+	return c.GetUserProfile("", userID)
+}
+
 // HabitTrackerTracking represents a single habit tracker entry for a day.
 type HabitTrackerTracking struct {
-	ID        int      `json:"id"`
-	Calories  *float64 `json:"calories"`
-	Date      Date     `json:"date"`
-	Protein   *float64 `json:"protein"`
-	Carbs     *float64 `json:"carbs"`
-	Fat       *float64 `json:"fat"`
-	Weight    *float64 `json:"weight"`
-	Sleep     *float64 `json:"sleep"`
-	Steps     *int     `json:"steps"`
-	Energy    *float64 `json:"energy"`
-	Hunger    *float64 `json:"hunger"`
-	Stress    *float64 `json:"stress"`
-	Notes     *string  `json:"notes"`
-	ClientID  int      `json:"client_id"`
-	CreatedAt string   `json:"created_at"`
-	UpdatedAt string   `json:"updated_at"`
+	ID        int           `json:"id"`
+	Calories  NullableFloat `json:"calories"`
+	Date      Date          `json:"date"`
+	Protein   NullableFloat `json:"protein"`
+	Carbs     NullableFloat `json:"carbs"`
+	Fat       NullableFloat `json:"fat"`
+	Weight    NullableFloat `json:"weight"`
+	Sleep     NullableFloat `json:"sleep"`
+	Steps     *int          `json:"steps"`
+	Energy    NullableFloat `json:"energy"`
+	Hunger    NullableFloat `json:"hunger"`
+	Stress    NullableFloat `json:"stress"`
+	Notes     *string       `json:"notes"`
+	ClientID  int           `json:"client_id"`
+	CreatedAt string        `json:"created_at"`
+	UpdatedAt string        `json:"updated_at"`
+	// CustomFields holds tracker keys the package doesn't model, such as
+	// metrics added through account customization.
+	CustomFields map[string]any `json:"-"`
 }
 
 // HabitTrackerResponse is the response from the habit_trackers endpoint.
+// The durations are nil when there is no period in that direction.
 type HabitTrackerResponse struct {
 	Trackings        []HabitTrackerTracking `json:"trackings"`
-	PreviousDuration map[string]any `json:"previous_duration"`
-	NextDuration     map[string]any `json:"next_duration"`
-	CurrentDuration  map[string]any `json:"current_duration"`
-	IsPrevious       bool                   `json:"is_previous"`
+	PreviousDuration *Duration              `json:"previous_duration"`
+	NextDuration     *Duration              `json:"next_duration"`
+	CurrentDuration  *Duration              `json:"current_duration"`
+	IsPrevious       Bool                   `json:"is_previous"`
+	// Included holds the sections expanded by HabitTrackerOptions.Include,
+	// by include name. It is nil unless includes were requested.
+	Included map[string]json.RawMessage `json:"-"`
+	// Raw is the full response object, for decoding sections not modeled here.
+	Raw json.RawMessage `json:"-"`
 }
 
 // GetHabitTrackers fetches habit tracker information for a client for the given date.
 func (c *Client) GetHabitTrackers(authToken string, clientID string, date Date) (*HabitTrackerResponse, error) {
-	var wrapper struct {
-		Response HabitTrackerResponse `json:"response"`
+	return c.GetHabitTrackersContext(context.Background(), authToken, clientID, date)
+}
+
+// GetHabitTrackersContext is GetHabitTrackers with a context for cancellation
+// and tracing.
+func (c *Client) GetHabitTrackersContext(ctx context.Context, authToken string, clientID string, date Date) (*HabitTrackerResponse, error) {
+	return c.getHabitTrackers(ctx, authToken, clientID, date, HabitTrackerOptions{})
+}
+
+// HabitTrackers is GetHabitTrackers with the token stored by Login or
+// SetAccessToken.
+func (c *Client) HabitTrackers(clientID string, date Date) (*HabitTrackerResponse, error) {
+	return c.GetHabitTrackers("", clientID, date)
+}
+
+// HabitTrackerOptions are optional parameters for GetHabitTrackersWithOptions.
+type HabitTrackerOptions struct {
+	// Include asks the API to expand related resources in the response,
+	// e.g. "comments". Sent as a comma-separated include parameter.
+	Include []string
+}
+
+// GetHabitTrackersWithOptions is GetHabitTrackers with optional parameters.
+// Each requested include the server expands is returned undecoded in
+// Included under its name; includes it doesn't support are simply absent.
+func (c *Client) GetHabitTrackersWithOptions(authToken, clientID string, date Date, opts HabitTrackerOptions) (*HabitTrackerResponse, error) {
+	return c.GetHabitTrackersWithOptionsContext(context.Background(), authToken, clientID, date, opts)
+}
+
+// GetHabitTrackersWithOptionsContext is GetHabitTrackersWithOptions with a
+// context for cancellation and tracing.
+func (c *Client) GetHabitTrackersWithOptionsContext(ctx context.Context, authToken, clientID string, date Date, opts HabitTrackerOptions) (*HabitTrackerResponse, error) {
+	return c.getHabitTrackers(ctx, authToken, clientID, date, opts)
+}
+
+func (c *Client) getHabitTrackers(ctx context.Context, authToken, clientID string, date Date, opts HabitTrackerOptions) (*HabitTrackerResponse, error) {
+	clientID, err := c.resolveClientID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]string{"date": date.String()}
+	if len(opts.Include) > 0 {
+		params["include"] = strings.Join(opts.Include, ",")
+	}
+	params = c.unitsParams(params)
+	path, err := endpoint(PathHabitTrackers, "clientID", clientID)
+	if err != nil {
+		return nil, err
+	}
+	var raw json.RawMessage
+	if err := c.doContext(ctx, http.MethodGet, authToken, path, params, nil, &raw); err != nil {
+		return nil, err
 	}
-	res, err := c.httpClient.R().
-		SetQueryParam("date", date.String()).
-		SetHeader("Authorization", "Bearer "+authToken).
-		SetResult(&wrapper).
-		Get("/clients/" + clientID + "/habit_trackers")
+	raw, err = unwrapResponse(raw)
 	if err != nil {
 		return nil, err
 	}
-	if err := checkStatus(res); err != nil {
+	var out HabitTrackerResponse
+	if err := c.decode(path, raw, &out); err != nil {
 		return nil, err
 	}
-	return &wrapper.Response, nil
+	out.Raw = raw
+	if len(opts.Include) > 0 {
+		var sections map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &sections); err != nil {
+			return nil, err
+		}
+		for _, name := range opts.Include {
+			if v, ok := sections[name]; ok {
+				if out.Included == nil {
+					out.Included = make(map[string]json.RawMessage)
+				}
+				out.Included[name] = v
+			}
+		}
+	}
+	return &out, nil
+}
+
+// unwrapResponse returns the object in the "response" envelope the
+// habit_trackers endpoint wraps its payload in. A body without the envelope,
+// as sent by some proxies and API versions, is returned as-is.
+func unwrapResponse(data json.RawMessage) (json.RawMessage, error) {
+	var wrapper struct {
+		Response json.RawMessage `json:"response"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, err
+	}
+	if len(wrapper.Response) == 0 || string(wrapper.Response) == "null" {
+		return data, nil
+	}
+	return wrapper.Response, nil
+}
+
+// ErrNoAdjacentPeriod is returned when a habit tracker response has no
+// previous or next period to navigate to, or is nil.
+var ErrNoAdjacentPeriod = errors.New("no adjacent habit tracker period")
+
+// GetHabitTrackersPrevious fetches the habit tracker period before current,
+// using its previous_duration metadata.
+func (c *Client) GetHabitTrackersPrevious(authToken string, clientID string, current *HabitTrackerResponse) (*HabitTrackerResponse, error) {
+	return c.GetHabitTrackersPreviousContext(context.Background(), authToken, clientID, current)
+}
+
+// GetHabitTrackersPreviousContext is GetHabitTrackersPrevious with a context
+// for cancellation and tracing.
+func (c *Client) GetHabitTrackersPreviousContext(ctx context.Context, authToken string, clientID string, current *HabitTrackerResponse) (*HabitTrackerResponse, error) {
+	if current == nil {
+		return nil, ErrNoAdjacentPeriod
+	}
+	return c.getAdjacentHabitTrackers(ctx, authToken, clientID, current.PreviousDuration)
+}
+
+// GetHabitTrackersNext fetches the habit tracker period after current,
+// using its next_duration metadata.
+func (c *Client) GetHabitTrackersNext(authToken string, clientID string, current *HabitTrackerResponse) (*HabitTrackerResponse, error) {
+	return c.GetHabitTrackersNextContext(context.Background(), authToken, clientID, current)
+}
+
+// GetHabitTrackersNextContext is GetHabitTrackersNext with a context for
+// cancellation and tracing.
+func (c *Client) GetHabitTrackersNextContext(ctx context.Context, authToken string, clientID string, current *HabitTrackerResponse) (*HabitTrackerResponse, error) {
+	if current == nil {
+		return nil, ErrNoAdjacentPeriod
+	}
+	return c.getAdjacentHabitTrackers(ctx, authToken, clientID, current.NextDuration)
+}
+
+func (c *Client) getAdjacentHabitTrackers(ctx context.Context, authToken, clientID string, duration *Duration) (*HabitTrackerResponse, error) {
+	date, ok := duration.startDate()
+	if !ok {
+		return nil, ErrNoAdjacentPeriod
+	}
+	return c.GetHabitTrackersContext(ctx, authToken, clientID, date)
 }
 
 // HabitTrackingUpdateInput is the payload for updating a habit tracker entry for a day.
 // Date is required; other fields are optional and only sent when set (omitempty).
+//
+// Each field has three states: nil leaves the stored value unchanged, a
+// non-nil pointer sets it, and listing the field's JSON key in Clear sends
+// an explicit null to erase it. Clear wins over a value set for the same key.
 type HabitTrackingUpdateInput struct {
 	Date   Date     `json:"date"`
 	Steps  *int     `json:"steps,omitempty"`
@@ -250,23 +648,47 @@ type HabitTrackingUpdateInput struct {
 	Hunger   *float64 `json:"hunger,omitempty"`
 	Stress   *float64 `json:"stress,omitempty"`
 	Notes    *string  `json:"notes,omitempty"`
+	// CustomFields are sent alongside the modeled fields. Keys that collide
+	// with a modeled field are ignored.
+	CustomFields map[string]any `json:"-"`
+	// Clear lists JSON keys (e.g. "weight", "notes") to set to null.
+	Clear []string `json:"-"`
 }
 
+// Float64Ptr returns a pointer to f, for setting optional payload fields inline.
+func Float64Ptr(f float64) *float64 { return &f }
+
+// IntPtr returns a pointer to i, for setting optional payload fields inline.
+func IntPtr(i int) *int { return &i }
+
+// StringPtr returns a pointer to s, for setting optional payload fields inline.
+func StringPtr(s string) *string { return &s }
+
 // UpdateHabitTracker updates the habit tracker entry for the given client and tracking ID.
+// The input is validated first; see HabitTrackingUpdateInput.Validate.
 func (c *Client) UpdateHabitTracker(authToken string, clientID string, trackingID string, input HabitTrackingUpdateInput) (*HabitTrackerTracking, error) {
+	return c.UpdateHabitTrackerContext(context.Background(), authToken, clientID, trackingID, input)
+}
+
+// UpdateHabitTrackerContext is UpdateHabitTracker with a context for
+// cancellation and tracing.
+func (c *Client) UpdateHabitTrackerContext(ctx context.Context, authToken string, clientID string, trackingID string, input HabitTrackingUpdateInput) (*HabitTrackerTracking, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
 	body := struct {
 		HabitTracking HabitTrackingUpdateInput `json:"habit_tracking"`
 	}{HabitTracking: input}
+	clientID, err := c.resolveClientID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
 	var out HabitTrackerTracking
-	res, err := c.httpClient.R().
-		SetHeader("Authorization", "Bearer "+authToken).
-		SetBody(body).
-		SetResult(&out).
-		Put("/clients/" + clientID + "/habit_trackers/" + trackingID)
+	path, err := endpoint(PathHabitTracker, "clientID", clientID, "trackingID", trackingID)
 	if err != nil {
 		return nil, err
 	}
-	if err := checkStatus(res); err != nil {
+	if err := c.doContext(ctx, http.MethodPut, authToken, path, nil, body, &out); err != nil {
 		return nil, err
 	}
 	return &out, nil
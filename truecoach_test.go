@@ -0,0 +1,48 @@
+package truecoach
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestGetHabitTrackersEnvelope(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"wrapped", `{"response":{"trackings":[{"id":7,"date":"2026-04-19"}],"is_previous":true}}`},
+		{"bare", `{"trackings":[{"id":7,"date":"2026-04-19"}],"is_previous":true}`},
+		{"null envelope", `{"response":null,"trackings":[{"id":7,"date":"2026-04-19"}],"is_previous":true}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, http.StatusOK, tt.body)
+			})
+			resp, err := c.GetHabitTrackers("token", "5", Today())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Trackings) != 1 || resp.Trackings[0].ID != 7 || !resp.IsPrevious {
+				t.Errorf("got %+v, want one tracking with ID 7 and IsPrevious", resp)
+			}
+		})
+	}
+}
+
+func TestGetHabitTrackersAdjacentNil(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+		writeJSON(w, http.StatusOK, `{}`)
+	})
+	if _, err := c.GetHabitTrackersPrevious("token", "5", nil); !errors.Is(err, ErrNoAdjacentPeriod) {
+		t.Errorf("Previous(nil): err = %v, want ErrNoAdjacentPeriod", err)
+	}
+	if _, err := c.GetHabitTrackersNext("token", "5", nil); !errors.Is(err, ErrNoAdjacentPeriod) {
+		t.Errorf("Next(nil): err = %v, want ErrNoAdjacentPeriod", err)
+	}
+	if _, err := c.GetHabitTrackersNext("token", "5", &HabitTrackerResponse{}); !errors.Is(err, ErrNoAdjacentPeriod) {
+		t.Errorf("Next without next_duration: err = %v, want ErrNoAdjacentPeriod", err)
+	}
+}
@@ -0,0 +1,62 @@
+package truecoach
+
+import (
+	"errors"
+	"fmt"
+)
+
+// hoursPerDay bounds the sleep metric.
+const hoursPerDay = 24
+
+// ValidationResult lists the problems ValidateHabitTracker found. Problems
+// can be matched with errors.Is, e.g. against ErrScaleOutOfRange.
+type ValidationResult struct {
+	Problems []error
+}
+
+// OK reports whether no problems were found.
+func (r ValidationResult) OK() bool { return len(r.Problems) == 0 }
+
+// Err joins the problems into one error, or returns nil if there are none.
+func (r ValidationResult) Err() error { return errors.Join(r.Problems...) }
+
+// ValidateHabitTracker checks an entry locally, without calling the API,
+// so import tools can report every bad row up front. It checks that a date
+// is set, that scale ratings are within ScaleMin..ScaleMax, that amounts
+// are not negative and that sleep fits in a day.
+func ValidateHabitTracker(entry HabitTrackerTracking) ValidationResult {
+	var r ValidationResult
+	add := func(err error) {
+		if err != nil {
+			r.Problems = append(r.Problems, err)
+		}
+	}
+	if entry.Date.IsZero() {
+		add(errors.New("date is required"))
+	}
+	add(checkScale("energy", entry.Energy.Ptr()))
+	add(checkScale("hunger", entry.Hunger.Ptr()))
+	add(checkScale("stress", entry.Stress.Ptr()))
+	for _, f := range []struct {
+		name  string
+		value NullableFloat
+	}{
+		{"calories", entry.Calories},
+		{"protein", entry.Protein},
+		{"carbs", entry.Carbs},
+		{"fat", entry.Fat},
+		{"weight", entry.Weight},
+		{"sleep", entry.Sleep},
+	} {
+		if f.value.Valid && f.value.Value < 0 {
+			add(fmt.Errorf("%s=%v is negative", f.name, f.value.Value))
+		}
+	}
+	if entry.Steps != nil && *entry.Steps < 0 {
+		add(fmt.Errorf("steps=%d is negative", *entry.Steps))
+	}
+	if entry.Sleep.Valid && entry.Sleep.Value > hoursPerDay {
+		add(fmt.Errorf("sleep=%v is more than %d hours", entry.Sleep.Value, hoursPerDay))
+	}
+	return r
+}